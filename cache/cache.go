@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Entry is a rendered response body together with its validators
+type Entry struct {
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+	expires      time.Time
+}
+
+// Store is an in-memory response cache with a time to live per entry.
+// Invalidate expires every entry; it is cheap enough to call on any write.
+type Store struct {
+	TTL time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+// PublicMenu caches the responses of the unauthenticated menu API
+var PublicMenu = NewStore(time.Minute)
+
+// NewStore creates an empty store whose entries expire after ttl
+func NewStore(ttl time.Duration) *Store {
+	return &Store{TTL: ttl, entries: map[string]Entry{}}
+}
+
+// Get returns the entry stored under key if it has not expired
+func (s *Store) Get(key string) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[key]
+	if !ok || !time.Now().Before(entry.expires) {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Set stores body under key and returns the entry with its ETag and Last-Modified.
+// Rebuilding identical content keeps the previous Last-Modified so clients still get 304s.
+func (s *Store) Set(key string, body []byte) Entry {
+	return s.SetUntil(key, body, time.Time{})
+}
+
+// SetUntil stores body like Set, but lets it expire at until when that comes before the TTL,
+// for content known to change at a given moment. A zero until means no such moment.
+func (s *Store) SetUntil(key string, body []byte, until time.Time) Entry {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	now := time.Now()
+	expires := now.Add(s.TTL)
+	if !until.IsZero() && until.Before(expires) {
+		expires = until
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lastModified := now.Truncate(time.Second)
+	if previous, ok := s.entries[key]; ok && previous.ETag == etag {
+		lastModified = previous.LastModified
+	}
+
+	entry := Entry{Body: body, ETag: etag, LastModified: lastModified, expires: expires}
+	s.entries[key] = entry
+	return entry
+}
+
+// MaxAge returns how long the entry stays fresh from now on
+func (e Entry) MaxAge() time.Duration {
+	return max(time.Until(e.expires), 0)
+}
+
+// Invalidate expires all cached entries; their validators are kept for the next Set
+func (s *Store) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.entries {
+		entry.expires = time.Time{}
+		s.entries[key] = entry
+	}
+}
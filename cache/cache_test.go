@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetUntil(t *testing.T) {
+	store := NewStore(time.Hour)
+
+	entry := store.SetUntil("soon", []byte("a"), time.Now().Add(-time.Second))
+	if _, ok := store.Get("soon"); ok {
+		t.Error("entry past its until should have expired")
+	}
+	if entry.MaxAge() != 0 {
+		t.Errorf("max age = %v, want 0", entry.MaxAge())
+	}
+
+	entry = store.SetUntil("later", []byte("b"), time.Now().Add(2*time.Hour))
+	if _, ok := store.Get("later"); !ok {
+		t.Error("entry should be cached")
+	}
+	if entry.MaxAge() > time.Hour {
+		t.Errorf("max age = %v, want at most the TTL", entry.MaxAge())
+	}
+}
+
+func TestSetKeepsLastModifiedForIdenticalBody(t *testing.T) {
+	store := NewStore(time.Hour)
+	first := store.Set("key", []byte("body"))
+	store.Invalidate()
+	if _, ok := store.Get("key"); ok {
+		t.Error("invalidated entry should have expired")
+	}
+	second := store.Set("key", []byte("body"))
+	if second.ETag != first.ETag || !second.LastModified.Equal(first.LastModified) {
+		t.Errorf("rebuilt entry changed validators: %v %v", first, second)
+	}
+}
@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Struct to hold the slot choices for a bundle price preview
+type BundleSelectionPack struct {
+	Selections []models.BundleSelection `json:"selections"`
+}
+
+// Get all bundles, optionally filtered by ?menu_id=
+func GetBundles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{}
+		if menuID := c.Query("menu_id"); menuID != "" {
+			filter["menu_id"] = menuID
+		}
+
+		result, err := database.BundleCollection.Find(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing bundles"})
+			return
+		}
+
+		allBundles := []models.Bundle{}
+		if err = result.All(ctx, &allBundles); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing bundles"})
+			return
+		}
+
+		c.JSON(http.StatusOK, allBundles)
+	}
+}
+
+// Get a single bundle by ID
+func GetBundle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var bundle models.Bundle
+		err := database.BundleCollection.FindOne(ctx, bson.M{"bundle_id": c.Param("bundle_id")}).Decode(&bundle)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bundle not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, bundle)
+	}
+}
+
+// Create a new bundle
+func CreateBundle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var bundle models.Bundle
+		if err := c.BindJSON(&bundle); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(bundle)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if err := validateBundleDefinition(ctx, bundle); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		bundle.CreatedAt = time.Now()
+		bundle.UpdatedAt = time.Now()
+		bundle.ID = primitive.NewObjectID()
+		bundle.BundleID = bundle.ID.Hex()
+
+		result, insertErr := database.BundleCollection.InsertOne(ctx, bundle)
+		if insertErr != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Bundle was not created"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Update a bundle
+func UpdateBundle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		bundleID := c.Param("bundle_id")
+		var existing models.Bundle
+		if err := database.BundleCollection.FindOne(ctx, bson.M{"bundle_id": bundleID}).Decode(&existing); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bundle not found"})
+			return
+		}
+
+		var bundle models.Bundle
+		if err := c.BindJSON(&bundle); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var updateObj primitive.D
+
+		if bundle.Name != nil {
+			updateObj = append(updateObj, bson.E{Key: "name", Value: bundle.Name})
+			existing.Name = bundle.Name
+		}
+		if bundle.Description != nil {
+			updateObj = append(updateObj, bson.E{Key: "description", Value: bundle.Description})
+		}
+		if bundle.Price != nil {
+			updateObj = append(updateObj, bson.E{Key: "price", Value: bundle.Price})
+			existing.Price = bundle.Price
+		}
+		if bundle.MenuID != nil {
+			updateObj = append(updateObj, bson.E{Key: "menu_id", Value: bundle.MenuID})
+			existing.MenuID = bundle.MenuID
+		}
+		if bundle.Slots != nil {
+			updateObj = append(updateObj, bson.E{Key: "slots", Value: bundle.Slots})
+			existing.Slots = bundle.Slots
+		}
+		if bundle.Available != nil {
+			updateObj = append(updateObj, bson.E{Key: "available", Value: bundle.Available})
+		}
+
+		// Validate the bundle as it will look after the update
+		validationErr := helpers.Validate.Struct(existing)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+		if err := validateBundleDefinition(ctx, existing); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		bundle.UpdatedAt = time.Now()
+		updateObj = append(updateObj, bson.E{Key: "updated_at", Value: bundle.UpdatedAt})
+
+		result, err := database.BundleCollection.UpdateOne(ctx, bson.M{"bundle_id": bundleID}, bson.D{{Key: "$set", Value: updateObj}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Bundle update failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// PriceBundle previews the price of a bundle for the given slot choices
+func PriceBundle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var bundle models.Bundle
+		if err := database.BundleCollection.FindOne(ctx, bson.M{"bundle_id": c.Param("bundle_id")}).Decode(&bundle); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bundle not found"})
+			return
+		}
+
+		var pack BundleSelectionPack
+		if err := c.ShouldBindJSON(&pack); err != nil && c.Request.ContentLength > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		available, err := bundleFoodAvailability(ctx, bundle)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while checking bundle availability"})
+			return
+		}
+
+		price, selections, err := ResolveBundleSelections(bundle, pack.Selections, available)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"bundle_id": bundle.BundleID, "price": price, "selections": selections})
+	}
+}
+
+// ResolveBundleSelections fills every slot of a bundle from the given choices (or the slot default),
+// rejects foods that are not allowed substitutions or not available and returns the bundle price including upcharges.
+// available tells which foods can currently be ordered (see bundleFoodAvailability).
+func ResolveBundleSelections(bundle models.Bundle, choices []models.BundleSelection, available map[string]bool) (money.Money, []models.BundleSelection, error) {
+	if bundle.Available != nil && !*bundle.Available {
+		return money.Money{}, nil, fmt.Errorf("bundle %s is not available", bundle.BundleID)
+	}
+
+	chosen := map[string]string{}
+	for _, choice := range choices {
+		if _, dup := chosen[choice.Slot]; dup {
+			return money.Money{}, nil, fmt.Errorf("slot %q selected more than once", choice.Slot)
+		}
+		chosen[choice.Slot] = choice.FoodID
+	}
+
+	price := *bundle.Price
+	selections := make([]models.BundleSelection, 0, len(bundle.Slots))
+	for _, slot := range bundle.Slots {
+		quantity := max(slot.Quantity, 1)
+		selection := models.BundleSelection{Slot: slot.Name, FoodID: slot.DefaultFoodID, Quantity: quantity, Upcharge: money.Zero(price.Currency)}
+
+		if foodID, ok := chosen[slot.Name]; ok && foodID != "" && foodID != slot.DefaultFoodID {
+			allowed := false
+			for _, option := range slot.Options {
+				if option.FoodID == foodID {
+					selection.FoodID = foodID
+					selection.Upcharge = option.Upcharge
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return money.Money{}, nil, fmt.Errorf("food %s is not an allowed choice for slot %q", foodID, slot.Name)
+			}
+		}
+		delete(chosen, slot.Name)
+		if !available[selection.FoodID] {
+			return money.Money{}, nil, fmt.Errorf("food %s for slot %q is not available", selection.FoodID, slot.Name)
+		}
+
+		total, err := price.Add(selection.Upcharge.Mul(int64(quantity)))
+		if err != nil {
+			return money.Money{}, nil, err
+		}
+		price = total
+		selections = append(selections, selection)
+	}
+
+	for slot := range chosen {
+		return money.Money{}, nil, fmt.Errorf("bundle has no slot %q", slot)
+	}
+
+	return price, selections, nil
+}
+
+// bundleFoodAvailability reports for every food a bundle references whether it can be ordered.
+// Foods that no longer exist are missing from the map and so count as unavailable.
+func bundleFoodAvailability(ctx context.Context, bundle models.Bundle) (map[string]bool, error) {
+	ids := []string{}
+	for _, slot := range bundle.Slots {
+		ids = append(ids, slot.DefaultFoodID)
+		for _, option := range slot.Options {
+			ids = append(ids, option.FoodID)
+		}
+	}
+
+	foods, err := findByKey(ctx, database.FoodCollection, "food_id", ids)
+	if err != nil {
+		return nil, err
+	}
+	available := map[string]bool{}
+	for id, food := range foods {
+		flag, ok := food["available"].(bool)
+		available[id] = !ok || flag
+	}
+	return available, nil
+}
+
+// validateBundleDefinition checks that the menu and every food referenced by the bundle exist
+func validateBundleDefinition(ctx context.Context, bundle models.Bundle) error {
+	count, err := database.MenuCollection.CountDocuments(ctx, bson.M{"menu_id": *bundle.MenuID})
+	if err != nil || count == 0 {
+		return fmt.Errorf("menu %s not found", *bundle.MenuID)
+	}
+
+	slotNames := map[string]bool{}
+	foodIDs := map[string]bool{}
+	for _, slot := range bundle.Slots {
+		if slotNames[slot.Name] {
+			return fmt.Errorf("duplicate slot name %q", slot.Name)
+		}
+		slotNames[slot.Name] = true
+		foodIDs[slot.DefaultFoodID] = true
+		for _, option := range slot.Options {
+			foodIDs[option.FoodID] = true
+		}
+	}
+
+	ids := make([]string, 0, len(foodIDs))
+	for id := range foodIDs {
+		ids = append(ids, id)
+	}
+	count, err = database.FoodCollection.CountDocuments(ctx, bson.M{"food_id": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	if int(count) != len(ids) {
+		return fmt.Errorf("bundle references food items that do not exist")
+	}
+
+	return nil
+}
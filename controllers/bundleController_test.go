@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"strings"
+	"testing"
+)
+
+func TestResolveBundleSelections(t *testing.T) {
+	price := money.New(1000, "USD")
+	bundle := models.Bundle{
+		BundleID: "meal",
+		Price:    &price,
+		Slots: []models.BundleSlot{
+			{Name: "Main", DefaultFoodID: "burger"},
+			{Name: "Side", DefaultFoodID: "fries", Options: []models.BundleOption{
+				{FoodID: "salad", Upcharge: money.New(150, "USD")},
+				{FoodID: "rings", Upcharge: money.New(100, "USD")},
+			}},
+			{Name: "Drink", DefaultFoodID: "cola", Quantity: 2, Options: []models.BundleOption{
+				{FoodID: "shake", Upcharge: money.New(200, "USD")},
+			}},
+		},
+	}
+	allAvailable := map[string]bool{"burger": true, "fries": true, "salad": true, "rings": true, "cola": true, "shake": true}
+	unavailable := false
+
+	tests := []struct {
+		name      string
+		bundle    func(models.Bundle) models.Bundle
+		choices   []models.BundleSelection
+		available map[string]bool
+		want      int64
+		wantFoods []string
+		wantErr   string
+	}{
+		{name: "defaults", want: 1000, wantFoods: []string{"burger", "fries", "cola"}},
+		{name: "default chosen explicitly", choices: []models.BundleSelection{{Slot: "Side", FoodID: "fries"}}, want: 1000, wantFoods: []string{"burger", "fries", "cola"}},
+		{name: "substitution with upcharge", choices: []models.BundleSelection{{Slot: "Side", FoodID: "salad"}}, want: 1150, wantFoods: []string{"burger", "salad", "cola"}},
+		{name: "upcharge per portion", choices: []models.BundleSelection{{Slot: "Drink", FoodID: "shake"}}, want: 1400, wantFoods: []string{"burger", "fries", "shake"}},
+		{name: "not an option", choices: []models.BundleSelection{{Slot: "Side", FoodID: "shake"}}, wantErr: "not an allowed choice"},
+		{name: "slot twice", choices: []models.BundleSelection{{Slot: "Side", FoodID: "salad"}, {Slot: "Side", FoodID: "rings"}}, wantErr: "more than once"},
+		{name: "unknown slot", choices: []models.BundleSelection{{Slot: "Dessert", FoodID: "cake"}}, wantErr: "no slot"},
+		{
+			name:    "bundle unavailable",
+			bundle:  func(b models.Bundle) models.Bundle { b.Available = &unavailable; return b },
+			wantErr: "bundle meal is not available",
+		},
+		{
+			name:      "default component unavailable",
+			available: map[string]bool{"burger": true, "fries": false, "salad": true, "rings": true, "cola": true, "shake": true},
+			wantErr:   `food fries for slot "Side" is not available`,
+		},
+		{
+			name:      "unavailable default replaced",
+			choices:   []models.BundleSelection{{Slot: "Side", FoodID: "rings"}},
+			available: map[string]bool{"burger": true, "fries": false, "rings": true, "cola": true},
+			want:      1100,
+			wantFoods: []string{"burger", "rings", "cola"},
+		},
+		{
+			name:      "chosen component missing",
+			choices:   []models.BundleSelection{{Slot: "Drink", FoodID: "shake"}},
+			available: map[string]bool{"burger": true, "fries": true, "cola": true},
+			wantErr:   `food shake for slot "Drink" is not available`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := bundle
+			if tt.bundle != nil {
+				b = tt.bundle(b)
+			}
+			available := tt.available
+			if available == nil {
+				available = allAvailable
+			}
+
+			got, selections, err := ResolveBundleSelections(b, tt.choices, available)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Amount != tt.want || got.Currency != "USD" {
+				t.Errorf("price = %v, want %d USD", got, tt.want)
+			}
+			if len(selections) != len(tt.wantFoods) {
+				t.Fatalf("got %d selections, want %d", len(selections), len(tt.wantFoods))
+			}
+			for i, selection := range selections {
+				if selection.FoodID != tt.wantFoods[i] {
+					t.Errorf("slot %s = %s, want %s", selection.Slot, selection.FoodID, tt.wantFoods[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,441 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Struct to hold a reorder request; IDs are listed in their new display order
+type ReorderPack struct {
+	ParentID *string  `json:"parent_id"`
+	IDs      []string `json:"ids" validate:"required,min=1,unique"`
+}
+
+// Struct to hold one node of a nested menu tree
+type CategoryNode struct {
+	CategoryID string          `json:"category_id"`
+	Name       string          `json:"name"`
+	Position   int             `json:"position"`
+	Foods      []bson.M        `json:"foods"`
+	Children   []*CategoryNode `json:"children"`
+}
+
+// Get all categories, optionally filtered by ?menu_id=
+func GetCategories() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{}
+		if menuID := c.Query("menu_id"); menuID != "" {
+			filter["menu_id"] = menuID
+		}
+
+		opts := options.Find().SetSort(bson.D{{Key: "menu_id", Value: 1}, {Key: "position", Value: 1}})
+		result, err := database.CategoryCollection.Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing categories"})
+			return
+		}
+
+		allCategories := []models.Category{}
+		if err = result.All(ctx, &allCategories); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing categories"})
+			return
+		}
+
+		c.JSON(http.StatusOK, allCategories)
+	}
+}
+
+// Create a new category
+func CreateCategory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var category models.Category
+		if err := c.BindJSON(&category); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(category)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		count, err := database.MenuCollection.CountDocuments(ctx, bson.M{"menu_id": *category.MenuID})
+		if err != nil || count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+			return
+		}
+
+		if category.ParentID != nil {
+			if _, err := findCategory(ctx, *category.ParentID, *category.MenuID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Parent category not found in this menu"})
+				return
+			}
+		}
+
+		// New categories go to the end of their siblings unless a position was given
+		if category.Position == nil {
+			siblings, err := database.CategoryCollection.CountDocuments(ctx, bson.M{"menu_id": *category.MenuID, "parent_id": category.ParentID})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while positioning category"})
+				return
+			}
+			position := int(siblings)
+			category.Position = &position
+		}
+
+		category.CreatedAt = time.Now()
+		category.UpdatedAt = time.Now()
+		category.ID = primitive.NewObjectID()
+		category.CategoryID = category.ID.Hex()
+
+		result, insertErr := database.CategoryCollection.InsertOne(ctx, category)
+		if insertErr != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Category was not created"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Update a category (name, parent or position)
+func UpdateCategory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		categoryID := c.Param("category_id")
+		var existing models.Category
+		if err := database.CategoryCollection.FindOne(ctx, bson.M{"category_id": categoryID}).Decode(&existing); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+
+		var category models.Category
+		if err := c.BindJSON(&category); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var updateObj primitive.D
+
+		if category.Name != nil {
+			updateObj = append(updateObj, bson.E{Key: "name", Value: category.Name})
+		}
+
+		if category.ParentID != nil {
+			// An empty parent_id moves the category to the top level
+			var parentID *string
+			if *category.ParentID != "" {
+				if err := checkCategoryParent(ctx, existing, *category.ParentID); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				parentID = category.ParentID
+			}
+			updateObj = append(updateObj, bson.E{Key: "parent_id", Value: parentID})
+		}
+
+		if category.Position != nil {
+			updateObj = append(updateObj, bson.E{Key: "position", Value: category.Position})
+		}
+
+		category.UpdatedAt = time.Now()
+		updateObj = append(updateObj, bson.E{Key: "updated_at", Value: category.UpdatedAt})
+
+		result, err := database.CategoryCollection.UpdateOne(ctx, bson.M{"category_id": categoryID}, bson.D{{Key: "$set", Value: updateObj}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Category update failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Delete a category that has no sub-categories or foods left
+func DeleteCategory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		categoryID := c.Param("category_id")
+
+		children, err := database.CategoryCollection.CountDocuments(ctx, bson.M{"parent_id": categoryID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+			return
+		}
+		foods, err := database.FoodCollection.CountDocuments(ctx, bson.M{"category_id": categoryID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+			return
+		}
+		if children > 0 || foods > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Category still has sub-categories or food items"})
+			return
+		}
+
+		result, err := database.CategoryCollection.DeleteOne(ctx, bson.M{"category_id": categoryID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+			return
+		}
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+	}
+}
+
+// ReorderCategories sets the display order of the categories under one parent of a menu
+func ReorderCategories() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var pack ReorderPack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		validationErr := helpers.Validate.Struct(pack)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		filter := bson.M{"menu_id": c.Param("menu_id"), "parent_id": pack.ParentID}
+		result, err := applyOrder(ctx, database.CategoryCollection, "category_id", filter, pack.IDs)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// ReorderFoods sets the display order of the foods in a category
+func ReorderFoods() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var pack ReorderPack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		validationErr := helpers.Validate.Struct(pack)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		filter := bson.M{"category_id": c.Param("category_id")}
+		result, err := applyOrder(ctx, database.FoodCollection, "food_id", filter, pack.IDs)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// GetMenuTree returns a menu with its categories nested and foods sorted by position
+func GetMenuTree() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		menuID := c.Param("menu_id")
+		locale := requestLocale(c)
+
+		var menu models.Menu
+		if err := database.MenuCollection.FindOne(ctx, bson.M{"menu_id": menuID}).Decode(&menu); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+			return
+		}
+		localizeMenu(&menu, locale)
+
+		tree, uncategorized, err := MenuTree(ctx, menuID, locale, bson.M{})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while building the menu tree"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"menu": menu, "categories": tree, "uncategorized": uncategorized})
+	}
+}
+
+// MenuTree loads a menu's categories and foods (matching foodFilter) in a single pass each and nests them
+func MenuTree(ctx context.Context, menuID, locale string, foodFilter bson.M) ([]*CategoryNode, []bson.M, error) {
+	cursor, err := database.CategoryCollection.Find(ctx, bson.M{"menu_id": menuID})
+	if err != nil {
+		return nil, nil, err
+	}
+	var categories []bson.M
+	if err = cursor.All(ctx, &categories); err != nil {
+		return nil, nil, err
+	}
+
+	match := bson.M{"menu_id": menuID}
+	for k, v := range foodFilter {
+		match[k] = v
+	}
+	pipeline := append(mongo.Pipeline{{{Key: "$match", Value: match}}}, currentPriceStages(time.Now())...)
+	cursor, err = database.FoodCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, nil, err
+	}
+	var foods []bson.M
+	if err = cursor.All(ctx, &foods); err != nil {
+		return nil, nil, err
+	}
+
+	nodes := map[string]*CategoryNode{}
+	for _, category := range categories {
+		localizeDocument(category, locale, "name")
+		id, _ := category["category_id"].(string)
+		name, _ := category["name"].(string)
+		nodes[id] = &CategoryNode{
+			CategoryID: id,
+			Name:       name,
+			Position:   documentInt(category["position"]),
+			Foods:      []bson.M{},
+			Children:   []*CategoryNode{},
+		}
+	}
+
+	roots := []*CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category["category_id"].(string)]
+		parentID, _ := category["parent_id"].(string)
+		if parent, ok := nodes[parentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	uncategorized := []bson.M{}
+	for _, food := range foods {
+		localizeDocument(food, locale, "name", "description")
+		categoryID, _ := food["category_id"].(string)
+		if node, ok := nodes[categoryID]; ok {
+			node.Foods = append(node.Foods, food)
+		} else {
+			uncategorized = append(uncategorized, food)
+		}
+	}
+
+	sortFoods(uncategorized)
+	sortCategoryNodes(roots)
+	return roots, uncategorized, nil
+}
+
+// sortCategoryNodes orders a level of the tree (and everything below it) by position, then name
+func sortCategoryNodes(nodes []*CategoryNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Position != nodes[j].Position {
+			return nodes[i].Position < nodes[j].Position
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		sortFoods(node.Foods)
+		sortCategoryNodes(node.Children)
+	}
+}
+
+// sortFoods orders foods by position; foods without a position keep insertion order at the end
+func sortFoods(foods []bson.M) {
+	sort.SliceStable(foods, func(i, j int) bool {
+		pi, iok := foods[i]["position"]
+		pj, jok := foods[j]["position"]
+		if iok != jok {
+			return iok
+		}
+		return documentInt(pi) < documentInt(pj)
+	})
+}
+
+// applyOrder writes position = index for every id, after checking ids are exactly the documents matching filter
+func applyOrder(ctx context.Context, collection *mongo.Collection, idKey string, filter bson.M, ids []string) (*mongo.BulkWriteResult, error) {
+	count, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	matched, err := collection.CountDocuments(ctx, bson.M{"$and": bson.A{filter, bson.M{idKey: bson.M{"$in": ids}}}})
+	if err != nil {
+		return nil, err
+	}
+	if int(matched) != len(ids) || int(count) != len(ids) {
+		return nil, fmt.Errorf("ids must list every item of the group exactly once (%d given, %d expected)", len(ids), count)
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(ids))
+	for position, id := range ids {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{idKey: id}).
+			SetUpdate(bson.M{"$set": bson.M{"position": position, "updated_at": now}}))
+	}
+
+	return collection.BulkWrite(ctx, writes)
+}
+
+// findCategory loads a category and checks it belongs to the given menu
+func findCategory(ctx context.Context, categoryID, menuID string) (models.Category, error) {
+	var category models.Category
+	err := database.CategoryCollection.FindOne(ctx, bson.M{"category_id": categoryID, "menu_id": menuID}).Decode(&category)
+	return category, err
+}
+
+// checkCategoryParent rejects parents from other menus and moves that would create a cycle
+func checkCategoryParent(ctx context.Context, category models.Category, parentID string) error {
+	// A chain that is already broken by a cycle elsewhere must not loop forever
+	visited := map[string]bool{}
+	for current := parentID; current != ""; {
+		if current == category.CategoryID {
+			return errors.New("a category cannot be moved below itself")
+		}
+		if visited[current] {
+			return errors.New("parent category chain contains a cycle")
+		}
+		visited[current] = true
+		parent, err := findCategory(ctx, current, *category.MenuID)
+		if err != nil {
+			return errors.New("parent category not found in this menu")
+		}
+		if parent.ParentID == nil {
+			break
+		}
+		current = *parent.ParentID
+	}
+	return nil
+}
@@ -0,0 +1,374 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// eventHeartbeat is how often idle streams are pinged so proxies keep them open
+const eventHeartbeat = 25 * time.Second
+
+// eventResourceRoles limits resources to some roles; the others go to every staff role.
+// Invoices carry payment details, servers follow payments through the order status instead.
+var eventResourceRoles = map[string][]string{
+	"invoice": {"admin", "manager"},
+}
+
+// EventAllowedOrigins lists the browser origins (e.g. "https://kds.example.com") that may open event
+// WebSockets besides the API's own. Set from EVENT_ALLOWED_ORIGINS at startup.
+var EventAllowedOrigins []string
+
+// eventUpgrader upgrades WebSocket requests from allowed origins
+var eventUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkEventOrigin,
+}
+
+// checkEventOrigin accepts clients that send no Origin (not a browser), the API's own origin and the allowed ones
+func checkEventOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range EventAllowedOrigins {
+		if strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateStreamTicket exchanges the caller's token for a short-lived single-use ticket, passed as ?ticket=
+// to open an event stream from a browser, which can't send the token header there
+func CreateStreamTicket() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticket, expiresAt, err := helpers.IssueStreamTicket(helpers.SignedDetails{
+			Email:     c.GetString("email"),
+			FirstName: c.GetString("first_name"),
+			LastName:  c.GetString("last_name"),
+			Uid:       c.GetString("uid"),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Stream ticket could not be issued"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"ticket": ticket, "expires_at": expiresAt})
+	}
+}
+
+// Stream change events as Server-Sent Events. Filters: ?types=order,table (resources) and ?station_id=
+// (only that kitchen station's tickets and items). Reconnecting clients resume after the Last-Event-ID header
+// (or ?last_event_id=); a stream.reset event means events were missed and the client has to reload.
+func StreamEvents() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sub, missed, resumed, ok := subscribeEvents(c, c.GetHeader("Last-Event-ID"))
+		if !ok {
+			return
+		}
+		defer sub.Close()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		write := func(event events.Event) bool {
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("event %s: encoding failed: %v", event.ID, err)
+				return true
+			}
+			if event.ID != "" {
+				fmt.Fprintf(c.Writer, "id: %s\n", event.ID)
+			}
+			_, err = fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, data)
+			c.Writer.Flush()
+			return err == nil
+		}
+
+		if !resumed {
+			write(events.Event{Type: events.StreamReset, At: time.Now()})
+		}
+		for _, event := range missed {
+			if !write(event) {
+				return
+			}
+		}
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case event, open := <-sub.C:
+				if !open {
+					// Dropped for falling behind; the client reconnects with its last event ID
+					return
+				}
+				if !write(event) {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// Stream change events over a WebSocket, one JSON event per message. Takes the same filters as the
+// SSE stream; reconnecting clients resume with ?last_event_id=. Messages from the client are ignored.
+func StreamEventsWebSocket() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sub, missed, resumed, ok := subscribeEvents(c, "")
+		if !ok {
+			return
+		}
+		defer sub.Close()
+
+		conn, err := eventUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// The upgrader has already answered the request
+			return
+		}
+		defer conn.Close()
+
+		// Reading is only needed to notice the client going away
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		write := func(event events.Event) bool {
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			return conn.WriteJSON(event) == nil
+		}
+
+		if !resumed && !write(events.Event{Type: events.StreamReset, At: time.Now()}) {
+			return
+		}
+		for _, event := range missed {
+			if !write(event) {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case event, open := <-sub.C:
+				if !open {
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "fell behind, resume from the last event"),
+						time.Now().Add(time.Second))
+					return
+				}
+				if !write(event) {
+					return
+				}
+			case <-heartbeat.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// subscribeEvents builds the subscriber's filter from its role and query and subscribes it, answering
+// the request itself when the filter is invalid
+func subscribeEvents(c *gin.Context, lastEventID string) (*events.Subscription, []events.Event, bool, bool) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	role := c.GetString("role")
+	resources := map[string]bool{}
+	for _, resource := range events.Resources {
+		if roles, limited := eventResourceRoles[resource]; !limited || slices.Contains(roles, role) {
+			resources[resource] = true
+		}
+	}
+
+	if v := c.Query("types"); v != "" {
+		wanted := map[string]bool{}
+		for _, resource := range strings.Split(v, ",") {
+			if !slices.Contains(events.Resources, resource) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown event type: " + resource})
+				return nil, nil, false, false
+			}
+			if !resources[resource] {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to follow " + resource + " events"})
+				return nil, nil, false, false
+			}
+			wanted[resource] = true
+		}
+		resources = wanted
+	}
+
+	stationID := c.Query("station_id")
+	if stationID != "" && stationID != KitchenUnassigned && !kitchenStationExists(ctx, stationID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kitchen station not found"})
+		return nil, nil, false, false
+	}
+
+	filter := func(event events.Event) bool {
+		if !resources[event.Resource] {
+			return false
+		}
+		return stationID == "" || slices.Contains(event.StationIDs, stationID)
+	}
+
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	sub, missed, resumed := events.Default.Subscribe(filter, lastEventID)
+	return sub, missed, resumed, true
+}
+
+// publishOrderEvent publishes a change to an order
+func publishOrderEvent(eventType string, order models.Order) {
+	events.Publish(events.Event{
+		Type:       eventType,
+		ResourceID: order.OrderID,
+		OrderID:    order.OrderID,
+		TableID:    stringValue(order.TableID),
+		Data:       order,
+	})
+}
+
+// publishOrderItemEvent publishes a change to an order item, tagged with the stations preparing it
+func publishOrderItemEvent(ctx context.Context, eventType string, item models.OrderItem) {
+	events.Publish(events.Event{
+		Type:       eventType,
+		ResourceID: item.OrderItemID,
+		OrderID:    item.OrderID,
+		StationIDs: orderItemStations(ctx, item),
+		Data:       item,
+	})
+}
+
+// publishTableEvent publishes a change to a table
+func publishTableEvent(eventType string, table models.Table) {
+	events.Publish(events.Event{
+		Type:       eventType,
+		ResourceID: table.TableID,
+		TableID:    table.TableID,
+		Data:       table,
+	})
+}
+
+// publishInvoiceEvent publishes a change to an invoice
+func publishInvoiceEvent(eventType string, invoice models.Invoice) {
+	events.Publish(events.Event{
+		Type:       eventType,
+		ResourceID: invoice.InvoiceID,
+		OrderID:    invoice.OrderID,
+		Data:       invoice,
+	})
+}
+
+// publishKitchenTicketEvent publishes a change to a kitchen ticket
+func publishKitchenTicketEvent(eventType string, ticket models.KitchenTicket) {
+	events.Publish(events.Event{
+		Type:       eventType,
+		ResourceID: ticket.TicketID,
+		OrderID:    ticket.OrderID,
+		TableID:    stringValue(ticket.TableID),
+		StationIDs: []string{ticket.StationID},
+		Data:       ticket,
+	})
+}
+
+// publishOrderChange reloads an order after a write that didn't return it and publishes the change
+func publishOrderChange(ctx context.Context, eventType, orderID string) {
+	var order models.Order
+	err := database.OrderCollection.FindOne(ctx, bson.M{"order_id": orderID}).Decode(&order)
+	if err != nil {
+		log.Printf("order %s: loading for %s event failed: %v", orderID, eventType, err)
+		return
+	}
+	publishOrderEvent(eventType, order)
+}
+
+// publishTableChange reloads a table after a write that didn't return it and publishes the change
+func publishTableChange(ctx context.Context, eventType, tableID string) {
+	var table models.Table
+	err := database.TableCollection.FindOne(ctx, bson.M{"table_id": tableID}).Decode(&table)
+	if err != nil {
+		log.Printf("table %s: loading for %s event failed: %v", tableID, eventType, err)
+		return
+	}
+	publishTableEvent(eventType, table)
+}
+
+// orderItemStations returns the kitchen stations of the foods in an order item, combo components included
+func orderItemStations(ctx context.Context, item models.OrderItem) []string {
+	foodIDs := []string{}
+	if item.FoodID != nil {
+		foodIDs = append(foodIDs, *item.FoodID)
+	}
+	for _, selection := range item.Selections {
+		foodIDs = append(foodIDs, selection.FoodID)
+	}
+	if len(foodIDs) == 0 {
+		return nil
+	}
+
+	foods, err := findByKey(ctx, database.FoodCollection, "food_id", foodIDs)
+	if err != nil {
+		return nil
+	}
+	stations := []string{}
+	for _, food := range foods {
+		stationID, _ := food["station_id"].(string)
+		if stationID == "" {
+			stationID = KitchenUnassigned
+		}
+		if !slices.Contains(stations, stationID) {
+			stations = append(stations, stationID)
+		}
+	}
+	return stations
+}
+
+// stringValue dereferences an optional string
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckEventOrigin(t *testing.T) {
+	defer func(origins []string) { EventAllowedOrigins = origins }(EventAllowedOrigins)
+	EventAllowedOrigins = []string{"https://kds.example.com/"}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"https://api.example.com", true},
+		{"https://kds.example.com", true},
+		{"https://KDS.example.com", true},
+		{"https://evil.example.net", false},
+		{"https://kds.example.com.evil.net", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "https://api.example.com/events/ws", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if got := checkEventOrigin(r); got != tt.want {
+			t.Errorf("origin %q = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,602 @@
+package controllers
+
+import (
+	"context"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Struct to hold the tables that make up a section
+type SectionTablesPack struct {
+	TableIDs []string `json:"table_ids"`
+}
+
+// Struct to hold the staff user serving a table
+type ServerView struct {
+	UserID    string  `json:"user_id"`
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+}
+
+// Struct to hold a table as drawn on the floor plan
+type FloorTableView struct {
+	models.Table
+	Status string      `json:"status"`
+	Server *ServerView `json:"server"`
+}
+
+// Struct to hold an area of the floor plan with its tables
+type FloorAreaView struct {
+	Area   *models.FloorArea `json:"area"`
+	Tables []FloorTableView  `json:"tables"`
+}
+
+// Get all floor areas in display order
+func GetFloorAreas() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		areas, err := floorAreas(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing floor areas"})
+			return
+		}
+
+		c.JSON(http.StatusOK, areas)
+	}
+}
+
+// Create a floor area
+func CreateFloorArea() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var area models.FloorArea
+		if err := c.BindJSON(&area); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(area)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		area.CreatedAt = time.Now()
+		area.UpdatedAt = time.Now()
+		area.ID = primitive.NewObjectID()
+		area.AreaID = area.ID.Hex()
+
+		if _, err := database.FloorAreaCollection.InsertOne(ctx, area); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Floor area was not created"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, area)
+	}
+}
+
+// Update a floor area
+func UpdateFloorArea() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var area models.FloorArea
+		if err := c.BindJSON(&area); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var updateObj primitive.D
+		if area.Name != nil {
+			updateObj = append(updateObj, bson.E{Key: "name", Value: area.Name})
+		}
+		if area.Position != nil {
+			updateObj = append(updateObj, bson.E{Key: "position", Value: area.Position})
+		}
+		if area.Width > 0 {
+			updateObj = append(updateObj, bson.E{Key: "width", Value: area.Width})
+		}
+		if area.Height > 0 {
+			updateObj = append(updateObj, bson.E{Key: "height", Value: area.Height})
+		}
+		updateObj = append(updateObj, bson.E{Key: "updated_at", Value: time.Now()})
+
+		result, err := database.FloorAreaCollection.UpdateOne(ctx, bson.M{"area_id": c.Param("area_id")}, bson.D{{Key: "$set", Value: updateObj}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Floor area update failed"})
+			return
+		}
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Floor area not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Delete a floor area that has no tables or sections left
+func DeleteFloorArea() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		areaID := c.Param("area_id")
+		tables, err := database.TableCollection.CountDocuments(ctx, bson.M{"area_id": areaID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete floor area"})
+			return
+		}
+		sections, err := database.SectionCollection.CountDocuments(ctx, bson.M{"area_id": areaID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete floor area"})
+			return
+		}
+		if tables > 0 || sections > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Move the area's tables and sections first"})
+			return
+		}
+
+		result, err := database.FloorAreaCollection.DeleteOne(ctx, bson.M{"area_id": areaID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete floor area"})
+			return
+		}
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Floor area not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Floor area deleted successfully"})
+	}
+}
+
+// Get all sections, optionally filtered by ?area_id=
+func GetSections() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{}
+		if areaID := c.Query("area_id"); areaID != "" {
+			filter["area_id"] = areaID
+		}
+
+		result, err := database.SectionCollection.Find(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing sections"})
+			return
+		}
+
+		sections := []models.Section{}
+		if err = result.All(ctx, &sections); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing sections"})
+			return
+		}
+
+		c.JSON(http.StatusOK, sections)
+	}
+}
+
+// Create a section
+func CreateSection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var section models.Section
+		if err := c.BindJSON(&section); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(section)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if section.AreaID != nil && !floorAreaExists(ctx, *section.AreaID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Floor area not found"})
+			return
+		}
+
+		section.CreatedAt = time.Now()
+		section.UpdatedAt = time.Now()
+		section.ID = primitive.NewObjectID()
+		section.SectionID = section.ID.Hex()
+
+		if _, err := database.SectionCollection.InsertOne(ctx, section); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Section was not created"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, section)
+	}
+}
+
+// Update a section's name or area
+func UpdateSection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var section models.Section
+		if err := c.BindJSON(&section); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var updateObj primitive.D
+		if section.Name != nil {
+			updateObj = append(updateObj, bson.E{Key: "name", Value: section.Name})
+		}
+		if section.AreaID != nil {
+			if !floorAreaExists(ctx, *section.AreaID) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Floor area not found"})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "area_id", Value: section.AreaID})
+		}
+		updateObj = append(updateObj, bson.E{Key: "updated_at", Value: time.Now()})
+
+		result, err := database.SectionCollection.UpdateOne(ctx, bson.M{"section_id": c.Param("section_id")}, bson.D{{Key: "$set", Value: updateObj}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Section update failed"})
+			return
+		}
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Section not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Delete a section; its tables become unassigned
+func DeleteSection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		sectionID := c.Param("section_id")
+		result, err := database.SectionCollection.DeleteOne(ctx, bson.M{"section_id": sectionID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete section"})
+			return
+		}
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Section not found"})
+			return
+		}
+
+		_, err = database.TableCollection.UpdateMany(ctx, bson.M{"section_id": sectionID}, bson.M{"$unset": bson.M{"section_id": ""}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unassign the section's tables"})
+			return
+		}
+		_, err = database.AssignmentCollection.DeleteMany(ctx, bson.M{"section_id": sectionID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove the section's assignments"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Section deleted successfully"})
+	}
+}
+
+// SetSectionTables makes the given tables the whole section, releasing tables left out
+func SetSectionTables() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		sectionID := c.Param("section_id")
+		var pack SectionTablesPack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		count, err := database.SectionCollection.CountDocuments(ctx, bson.M{"section_id": sectionID})
+		if err != nil || count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Section not found"})
+			return
+		}
+		if pack.TableIDs == nil {
+			pack.TableIDs = []string{}
+		}
+
+		found, err := database.TableCollection.CountDocuments(ctx, bson.M{"table_id": bson.M{"$in": pack.TableIDs}})
+		if err != nil || int(found) != len(pack.TableIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Some tables were not found"})
+			return
+		}
+
+		now := time.Now()
+		_, err = database.TableCollection.UpdateMany(ctx,
+			bson.M{"section_id": sectionID, "table_id": bson.M{"$nin": pack.TableIDs}},
+			bson.M{"$unset": bson.M{"section_id": ""}, "$set": bson.M{"updated_at": now}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Section update failed"})
+			return
+		}
+		_, err = database.TableCollection.UpdateMany(ctx,
+			bson.M{"table_id": bson.M{"$in": pack.TableIDs}},
+			bson.M{"$set": bson.M{"section_id": sectionID, "updated_at": now}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Section update failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"section_id": sectionID, "table_ids": pack.TableIDs})
+	}
+}
+
+// Get shift assignments, optionally filtered by ?section_id=, ?user_id= and ?at=<RFC3339>
+func GetSectionAssignments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{}
+		if sectionID := c.Query("section_id"); sectionID != "" {
+			filter["section_id"] = sectionID
+		}
+		if userID := c.Query("user_id"); userID != "" {
+			filter["user_id"] = userID
+		}
+		if v := c.Query("at"); v != "" {
+			at, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid at timestamp, expected RFC3339"})
+				return
+			}
+			filter["starts_at"] = bson.M{"$lte": at}
+			filter["ends_at"] = bson.M{"$gt": at}
+		}
+
+		opts := options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}})
+		result, err := database.AssignmentCollection.Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing assignments"})
+			return
+		}
+
+		assignments := []models.SectionAssignment{}
+		if err = result.All(ctx, &assignments); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing assignments"})
+			return
+		}
+
+		c.JSON(http.StatusOK, assignments)
+	}
+}
+
+// CreateSectionAssignment puts a staff user in charge of a section for a shift
+func CreateSectionAssignment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var assignment models.SectionAssignment
+		if err := c.BindJSON(&assignment); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		assignment.SectionID = c.Param("section_id")
+
+		validationErr := helpers.Validate.Struct(assignment)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+		if !assignment.EndsAt.After(*assignment.StartsAt) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+			return
+		}
+
+		count, err := database.SectionCollection.CountDocuments(ctx, bson.M{"section_id": assignment.SectionID})
+		if err != nil || count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Section not found"})
+			return
+		}
+
+		var user models.User
+		if err := database.UserCollection.FindOne(ctx, bson.M{"user_id": *assignment.UserID}).Decode(&user); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+			return
+		}
+		if user.Role != "staff" && user.Role != "manager" && user.Role != "admin" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only staff can be assigned to sections"})
+			return
+		}
+
+		// One server per section at a time
+		overlap := bson.M{
+			"section_id": assignment.SectionID,
+			"starts_at":  bson.M{"$lt": assignment.EndsAt},
+			"ends_at":    bson.M{"$gt": assignment.StartsAt},
+		}
+		count, err = database.AssignmentCollection.CountDocuments(ctx, overlap)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while checking assignments"})
+			return
+		}
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Section already has a server during this shift"})
+			return
+		}
+
+		assignment.CreatedAt = time.Now()
+		assignment.ID = primitive.NewObjectID()
+		assignment.AssignmentID = assignment.ID.Hex()
+
+		if _, err := database.AssignmentCollection.InsertOne(ctx, assignment); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Assignment was not created"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, assignment)
+	}
+}
+
+// Delete a shift assignment
+func DeleteSectionAssignment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		result, err := database.AssignmentCollection.DeleteOne(ctx, bson.M{"assignment_id": c.Param("assignment_id")})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete assignment"})
+			return
+		}
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Assignment deleted successfully"})
+	}
+}
+
+// FloorPlan groups the tables by area and annotates them with their status and the server on shift at the given time.
+// Tables without an area are returned last under a nil area.
+func FloorPlan(ctx context.Context, at time.Time, areaID string) ([]FloorAreaView, error) {
+	areas, err := floorAreas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{}
+	if areaID != "" {
+		filter["area_id"] = areaID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "table_number", Value: 1}})
+	cursor, err := database.TableCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var tables []models.Table
+	if err = cursor.All(ctx, &tables); err != nil {
+		return nil, err
+	}
+
+	servers, err := serversOnShift(ctx, at)
+	if err != nil {
+		return nil, err
+	}
+
+	byArea := map[string][]FloorTableView{}
+	for _, table := range tables {
+		view := FloorTableView{Table: table, Status: TableStatus(table)}
+		if table.SectionID != nil {
+			view.Server = servers[*table.SectionID]
+		}
+		key := ""
+		if table.AreaID != nil {
+			key = *table.AreaID
+		}
+		byArea[key] = append(byArea[key], view)
+	}
+
+	plan := []FloorAreaView{}
+	for i := range areas {
+		if areaID != "" && areas[i].AreaID != areaID {
+			continue
+		}
+		views := byArea[areas[i].AreaID]
+		if views == nil {
+			views = []FloorTableView{}
+		}
+		plan = append(plan, FloorAreaView{Area: &areas[i], Tables: views})
+	}
+	if unplaced := byArea[""]; len(unplaced) > 0 && areaID == "" {
+		plan = append(plan, FloorAreaView{Tables: unplaced})
+	}
+
+	return plan, nil
+}
+
+// serversOnShift returns the staff user covering each section at the given time
+func serversOnShift(ctx context.Context, at time.Time) (map[string]*ServerView, error) {
+	cursor, err := database.AssignmentCollection.Find(ctx, bson.M{"starts_at": bson.M{"$lte": at}, "ends_at": bson.M{"$gt": at}})
+	if err != nil {
+		return nil, err
+	}
+	var assignments []models.SectionAssignment
+	if err = cursor.All(ctx, &assignments); err != nil {
+		return nil, err
+	}
+
+	userIDs := []string{}
+	for _, assignment := range assignments {
+		userIDs = append(userIDs, *assignment.UserID)
+	}
+	users := map[string]*ServerView{}
+	if len(userIDs) > 0 {
+		opts := options.Find().SetProjection(bson.M{"user_id": 1, "first_name": 1, "last_name": 1})
+		cursor, err = database.UserCollection.Find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}}, opts)
+		if err != nil {
+			return nil, err
+		}
+		var found []models.User
+		if err = cursor.All(ctx, &found); err != nil {
+			return nil, err
+		}
+		for _, user := range found {
+			users[user.UserID] = &ServerView{UserID: user.UserID, FirstName: user.FirstName, LastName: user.LastName}
+		}
+	}
+
+	servers := map[string]*ServerView{}
+	for _, assignment := range assignments {
+		if user, ok := users[*assignment.UserID]; ok {
+			servers[assignment.SectionID] = user
+		}
+	}
+	return servers, nil
+}
+
+// floorAreas loads every floor area in display order
+func floorAreas(ctx context.Context) ([]models.FloorArea, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := database.FloorAreaCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	areas := []models.FloorArea{}
+	if err = cursor.All(ctx, &areas); err != nil {
+		return nil, err
+	}
+	return areas, nil
+}
+
+// floorAreaExists reports whether a floor area with the given ID exists
+func floorAreaExists(ctx context.Context, areaID string) bool {
+	count, err := database.FloorAreaCollection.CountDocuments(ctx, bson.M{"area_id": areaID})
+	return err == nil && count > 0
+}
+
+// sectionExists reports whether a section with the given ID exists
+func sectionExists(ctx context.Context, sectionID string) bool {
+	count, err := database.SectionCollection.CountDocuments(ctx, bson.M{"section_id": sectionID})
+	return err == nil && count > 0
+}
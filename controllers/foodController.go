@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"golang-restaurant-management/database"
 	"golang-restaurant-management/helpers"
 	"golang-restaurant-management/models"
@@ -43,7 +44,8 @@ func GetFoods() gin.HandlerFunc {
 		limitStage := bson.D{{Key: "$limit", Value: recordPerPage}}
 
 		// Execute aggregation query
-		result, err := database.FoodCollection.Aggregate(ctx, mongo.Pipeline{matchStage, skipStage, limitStage})
+		pipeline := append(mongo.Pipeline{matchStage, skipStage, limitStage}, currentPriceStages(time.Now())...)
+		result, err := database.FoodCollection.Aggregate(ctx, pipeline)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing food items"})
 			return
@@ -54,6 +56,11 @@ func GetFoods() gin.HandlerFunc {
 			log.Fatal(err)
 		}
 
+		locale := requestLocale(c)
+		for _, food := range allFoods {
+			localizeDocument(food, locale, "name", "description")
+		}
+
 		c.JSON(http.StatusOK, allFoods)
 	}
 }
@@ -73,6 +80,14 @@ func GetFood() gin.HandlerFunc {
 			return
 		}
 
+		// Report the price in effect right now, which may come from a scheduled change
+		price, err := FoodPriceAt(ctx, foodID, time.Now())
+		if err == nil {
+			food.Price = &price
+		}
+
+		localizeFood(&food, requestLocale(c))
+
 		c.JSON(http.StatusOK, food)
 	}
 }
@@ -112,16 +127,30 @@ func CreateFood() gin.HandlerFunc {
 			return
 		}
 
+		if err := NormalizeNutrition(food.Nutrition); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Categories must belong to the food's menu
+		if food.CategoryID != nil {
+			if _, err := findCategory(ctx, *food.CategoryID, *food.MenuID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found in this menu"})
+				return
+			}
+		}
+
+		if food.StationID != nil && !kitchenStationExists(ctx, *food.StationID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Kitchen station not found"})
+			return
+		}
+
 		// Set timestamps
 		food.CreatedAt = time.Now()
 		food.UpdatedAt = time.Now()
 		food.ID = primitive.NewObjectID()
 		food.FoodID = food.ID.Hex()
 
-		// Round price safely
-		num := toFixed(*food.Price, 2)
-		food.Price = &num
-
 		// Insert into database
 		result, insertErr := database.FoodCollection.InsertOne(ctx, food)
 		if insertErr != nil {
@@ -129,6 +158,12 @@ func CreateFood() gin.HandlerFunc {
 			return
 		}
 
+		// Start the price history with the initial price
+		if _, err := recordFoodPrice(ctx, food.FoodID, *food.Price, food.CreatedAt, c.GetString("uid")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Food price history could not be created"})
+			return
+		}
+
 		c.JSON(http.StatusOK, result)
 	}
 }
@@ -158,14 +193,53 @@ func UpdateFood() gin.HandlerFunc {
 		}
 
 		if food.Price != nil {
-			num := toFixed(*food.Price, 2)
-			updateObj = append(updateObj, bson.E{Key: "price", Value: num})
+			if !food.Price.IsPositive() {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Price must be greater than 0"})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "price", Value: food.Price})
+		}
+
+		if food.Description != nil {
+			updateObj = append(updateObj, bson.E{Key: "description", Value: food.Description})
+		}
+
+		if food.Available != nil {
+			updateObj = append(updateObj, bson.E{Key: "available", Value: food.Available})
 		}
 
 		if food.FoodImage != nil {
 			updateObj = append(updateObj, bson.E{Key: "food_image", Value: food.FoodImage})
 		}
 
+		var existing models.Food
+		if err := database.FoodCollection.FindOne(ctx, bson.M{"food_id": foodID}).Decode(&existing); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while fetching the food item"})
+			return
+		}
+
+		// Guest-facing changes to foods of versioned menus go through the menu draft.
+		// Availability and kitchen station stay editable: the floor and kitchen change them during service.
+		contentEdit := food.Name != nil || food.Price != nil || food.Description != nil || food.FoodImage != nil ||
+			food.MenuID != nil || food.CategoryID != nil || food.Position != nil || food.Nutrition != nil ||
+			food.SizeFactors != nil || food.Modifiers != nil
+		if contentEdit {
+			for _, menuID := range []*string{existing.MenuID, food.MenuID} {
+				if menuID == nil {
+					continue
+				}
+				if err := checkDirectMenuEdit(ctx, *menuID); err != nil {
+					if errors.Is(err, ErrMenuVersioned) {
+						c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+						return
+					}
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while checking the menu"})
+					return
+				}
+			}
+		}
+
+		var unsetObj primitive.D
 		if food.MenuID != nil {
 			err := database.MenuCollection.FindOne(ctx, bson.M{"menu_id": *food.MenuID}).Decode(&menu)
 			if err != nil {
@@ -173,6 +247,65 @@ func UpdateFood() gin.HandlerFunc {
 				return
 			}
 			updateObj = append(updateObj, bson.E{Key: "menu_id", Value: food.MenuID})
+
+			// Categories belong to one menu, so a food moved elsewhere leaves its old category behind
+			moved := existing.MenuID == nil || *existing.MenuID != *food.MenuID
+			if moved && food.CategoryID == nil && existing.CategoryID != nil {
+				unsetObj = append(unsetObj, bson.E{Key: "category_id", Value: ""})
+			}
+		}
+
+		if food.CategoryID != nil {
+			// The category has to belong to the (possibly new) menu of the food
+			menuID := food.MenuID
+			if menuID == nil {
+				menuID = existing.MenuID
+			}
+			if menuID == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "menu_id is required to set a category"})
+				return
+			}
+			if _, err := findCategory(ctx, *food.CategoryID, *menuID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found in this menu"})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "category_id", Value: food.CategoryID})
+		}
+
+		if food.StationID != nil {
+			if !kitchenStationExists(ctx, *food.StationID) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Kitchen station not found"})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "station_id", Value: food.StationID})
+		}
+
+		if food.Position != nil {
+			updateObj = append(updateObj, bson.E{Key: "position", Value: food.Position})
+		}
+
+		if food.Nutrition != nil {
+			if err := NormalizeNutrition(food.Nutrition); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if validationErr := helpers.Validate.Struct(food.Nutrition); validationErr != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "nutrition", Value: food.Nutrition})
+		}
+
+		if food.SizeFactors != nil {
+			updateObj = append(updateObj, bson.E{Key: "size_factors", Value: food.SizeFactors})
+		}
+
+		if food.Modifiers != nil {
+			if validationErr := helpers.Validate.Var(food.Modifiers, "dive"); validationErr != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "modifiers", Value: food.Modifiers})
 		}
 
 		// Update timestamp
@@ -185,12 +318,24 @@ func UpdateFood() gin.HandlerFunc {
 		opt := options.UpdateOptions{Upsert: &upsert}
 
 		// Perform update
-		result, err := database.FoodCollection.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: updateObj}}, &opt)
+		update := bson.D{{Key: "$set", Value: updateObj}}
+		if len(unsetObj) > 0 {
+			update = append(update, bson.E{Key: "$unset", Value: unsetObj})
+		}
+		result, err := database.FoodCollection.UpdateOne(ctx, filter, update, &opt)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Food item update failed"})
 			return
 		}
 
+		// Price changes are versioned instead of silently overwritten
+		if food.Price != nil {
+			if _, err := recordFoodPrice(ctx, foodID, *food.Price, food.UpdatedAt, c.GetString("uid")); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Food price history update failed"})
+				return
+			}
+		}
+
 		c.JSON(http.StatusOK, result)
 	}
 }
@@ -219,8 +364,8 @@ func DeleteFood() gin.HandlerFunc {
 	}
 }
 
-// toFixed rounds a float to a given number of decimal places
+// toFixed rounds a float to a given number of decimal places (ratios only, money uses the money package)
 func toFixed(num float64, precision int) float64 {
 	output := math.Pow(10, float64(precision))
-	return float64(int(num*output+0.5)) / output
+	return math.Round(num*output) / output
 }
@@ -0,0 +1,626 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"golang-restaurant-management/repository"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Maximum accepted import payload (10 MiB)
+const maxImportSize = 10 << 20
+
+// Columns of the flat CSV import/export format, one food per row
+var menuCSVHeader = []string{
+	"menu_id", "menu_name", "menu_category",
+	"food_id", "food_name", "food_description", "price", "available", "position", "category_id", "image",
+}
+
+// Struct to hold a menu in the nested JSON import/export format
+type MenuTransfer struct {
+	MenuID   string         `json:"menu_id,omitempty"`
+	Name     string         `json:"name"`
+	Category string         `json:"category"`
+	Foods    []FoodTransfer `json:"foods"`
+}
+
+// Struct to hold a food in the nested JSON import/export format
+type FoodTransfer struct {
+	FoodID      string       `json:"food_id,omitempty"`
+	Name        *string      `json:"name"`
+	Description *string      `json:"description,omitempty"`
+	Price       *money.Money `json:"price"`
+	Available   *bool        `json:"available,omitempty"`
+	Position    *int         `json:"position,omitempty"`
+	CategoryID  *string      `json:"category_id,omitempty"`
+	Image       *string      `json:"image,omitempty"`
+}
+
+// Struct to hold a validation problem of one import row
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// Struct to hold the outcome of an import
+type ImportReport struct {
+	DryRun       bool             `json:"dry_run"`
+	Format       string           `json:"format"`
+	Rows         int              `json:"rows"`
+	MenusCreated int              `json:"menus_created"`
+	MenusUpdated int              `json:"menus_updated"`
+	FoodsCreated int              `json:"foods_created"`
+	FoodsUpdated int              `json:"foods_updated"`
+	Errors       []ImportRowError `json:"errors"`
+}
+
+// importMenu is a parsed menu with its foods; row numbers point back into the uploaded file
+type importMenu struct {
+	row   int
+	menu  MenuTransfer
+	foods []importFood
+}
+
+type importFood struct {
+	row      int
+	food     FoodTransfer
+	parseErr string
+}
+
+// plannedMenu / plannedFood are validated documents ready to be written
+type plannedMenu struct {
+	menu   models.Menu
+	create bool
+}
+
+type plannedFood struct {
+	food         models.Food
+	create       bool
+	priceChanged bool
+}
+
+// ImportMenus creates or updates menus and their foods from a CSV or JSON upload.
+// With ?dry_run=true nothing is written and the report lists every row that would fail.
+func ImportMenus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+		data, contentType, err := readImportPayload(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		format := c.Query("format")
+		if format == "" {
+			format = "json"
+			if strings.Contains(contentType, "csv") {
+				format = "csv"
+			}
+		}
+
+		var menus []importMenu
+		var rows int
+		switch format {
+		case "csv":
+			menus, rows, err = parseMenuCSV(data)
+		case "json":
+			menus, rows, err = parseMenuJSON(data)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report := ImportReport{DryRun: dryRun, Format: format, Rows: rows, Errors: []ImportRowError{}}
+		plannedMenus, plannedFoods := planMenuImport(ctx, menus, &report)
+
+		if dryRun {
+			c.JSON(http.StatusOK, report)
+			return
+		}
+		if len(report.Errors) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, report)
+			return
+		}
+
+		if err := applyMenuImport(ctx, plannedMenus, plannedFoods, c.GetString("uid")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Import failed: " + err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// ExportMenus exports menus and foods (current prices) as ?format=csv|json, optionally for one ?menu_id=
+func ExportMenus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{}
+		if menuID := c.Query("menu_id"); menuID != "" {
+			filter["menu_id"] = menuID
+		}
+
+		var menus []models.Menu
+		cursor, err := database.MenuCollection.Find(ctx, filter)
+		if err == nil {
+			err = cursor.All(ctx, &menus)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing menus"})
+			return
+		}
+
+		pipeline := append(mongo.Pipeline{{{Key: "$match", Value: filter}}}, currentPriceStages(time.Now())...)
+		var foods []models.Food
+		cursor, err = database.FoodCollection.Aggregate(ctx, pipeline)
+		if err == nil {
+			err = cursor.All(ctx, &foods)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing food items"})
+			return
+		}
+
+		transfers := make([]MenuTransfer, 0, len(menus))
+		index := map[string]int{}
+		for _, menu := range menus {
+			index[menu.MenuID] = len(transfers)
+			transfers = append(transfers, MenuTransfer{MenuID: menu.MenuID, Name: menu.Name, Category: menu.Category, Foods: []FoodTransfer{}})
+		}
+		for _, food := range foods {
+			if food.MenuID == nil {
+				continue
+			}
+			i, ok := index[*food.MenuID]
+			if !ok {
+				continue
+			}
+			transfers[i].Foods = append(transfers[i].Foods, FoodTransfer{
+				FoodID:      food.FoodID,
+				Name:        food.Name,
+				Description: food.Description,
+				Price:       food.Price,
+				Available:   food.Available,
+				Position:    food.Position,
+				CategoryID:  food.CategoryID,
+				Image:       food.FoodImage,
+			})
+		}
+
+		filename := "menus-" + time.Now().Format("20060102")
+		switch c.DefaultQuery("format", "json") {
+		case "json":
+			c.Header("Content-Disposition", `attachment; filename="`+filename+`.json"`)
+			c.JSON(http.StatusOK, transfers)
+		case "csv":
+			var buf bytes.Buffer
+			if err := writeMenuCSV(&buf, transfers); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write CSV"})
+				return
+			}
+			c.Header("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+			c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		}
+	}
+}
+
+// readImportPayload reads the upload from a multipart "file" field or from the raw request body
+func readImportPayload(c *gin.Context) ([]byte, string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", errors.New("multipart field 'file' is required")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", errors.New("could not read uploaded file")
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		contentType := header.Header.Get("Content-Type")
+		if strings.HasSuffix(strings.ToLower(header.Filename), ".csv") {
+			contentType = "text/csv"
+		}
+		return data, contentType, err
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, "", errors.New("could not read request body")
+	}
+	return data, c.ContentType(), nil
+}
+
+// parseMenuJSON reads the nested format; rows are numbered menu by menu, food by food
+func parseMenuJSON(data []byte) ([]importMenu, int, error) {
+	var transfers []MenuTransfer
+	if err := json.Unmarshal(data, &transfers); err != nil {
+		return nil, 0, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	menus := []importMenu{}
+	row := 0
+	for _, transfer := range transfers {
+		row++
+		menu := importMenu{row: row, menu: transfer}
+		for _, food := range transfer.Foods {
+			row++
+			menu.foods = append(menu.foods, importFood{row: row, food: food})
+		}
+		menus = append(menus, menu)
+	}
+	return menus, row, nil
+}
+
+// parseMenuCSV reads the flat format; consecutive rows with the same menu are grouped together
+func parseMenuCSV(data []byte) ([]importMenu, int, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, 0, errors.New("CSV file is empty")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	if _, ok := columns["menu_name"]; !ok {
+		if _, ok := columns["menu_id"]; !ok {
+			return nil, 0, errors.New("CSV header must contain menu_id or menu_name")
+		}
+	}
+
+	get := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+
+	menus := []importMenu{}
+	byKey := map[string]int{}
+	for i, record := range records[1:] {
+		row := i + 2 // Row numbers match the spreadsheet, header is row 1
+
+		key := get(record, "menu_id")
+		if key == "" {
+			key = "name:" + strings.ToLower(get(record, "menu_name"))
+		}
+		idx, ok := byKey[key]
+		if !ok {
+			idx = len(menus)
+			byKey[key] = idx
+			menus = append(menus, importMenu{row: row, menu: MenuTransfer{
+				MenuID:   get(record, "menu_id"),
+				Name:     get(record, "menu_name"),
+				Category: get(record, "menu_category"),
+			}})
+		}
+
+		// Menu-only rows carry no food
+		if get(record, "food_id") == "" && get(record, "food_name") == "" {
+			continue
+		}
+
+		food := FoodTransfer{
+			FoodID:      get(record, "food_id"),
+			Name:        optional(get(record, "food_name")),
+			Description: optional(get(record, "food_description")),
+			CategoryID:  optional(get(record, "category_id")),
+			Image:       optional(get(record, "image")),
+		}
+		if v := get(record, "price"); v != "" {
+			price, err := money.Parse(v, money.DefaultCurrency(), money.Rounding())
+			if err != nil {
+				menus[idx].foods = append(menus[idx].foods, importFood{row: row, parseErr: "invalid price " + v})
+				continue
+			}
+			food.Price = &price
+		}
+		if v := get(record, "available"); v != "" {
+			available, err := strconv.ParseBool(v)
+			if err != nil {
+				menus[idx].foods = append(menus[idx].foods, importFood{row: row, parseErr: "invalid available " + v})
+				continue
+			}
+			food.Available = &available
+		}
+		if v := get(record, "position"); v != "" {
+			position, err := strconv.Atoi(v)
+			if err != nil {
+				menus[idx].foods = append(menus[idx].foods, importFood{row: row, parseErr: "invalid position " + v})
+				continue
+			}
+			food.Position = &position
+		}
+		menus[idx].foods = append(menus[idx].foods, importFood{row: row, food: food})
+	}
+
+	return menus, len(records) - 1, nil
+}
+
+// planMenuImport matches rows against existing documents, merges them and validates the result.
+// Menus match by menu_id or name, foods by food_id or name within the menu.
+func planMenuImport(ctx context.Context, menus []importMenu, report *ImportReport) ([]plannedMenu, []plannedFood) {
+	fail := func(row int, format string, args ...interface{}) {
+		report.Errors = append(report.Errors, ImportRowError{Row: row, Error: fmt.Sprintf(format, args...)})
+	}
+
+	var plannedMenus []plannedMenu
+	var plannedFoods []plannedFood
+	now := time.Now()
+
+	for _, item := range menus {
+		var menu models.Menu
+		create := false
+
+		filter := bson.M{"name": item.menu.Name}
+		if item.menu.MenuID != "" {
+			filter = bson.M{"menu_id": item.menu.MenuID}
+		}
+		err := database.MenuCollection.FindOne(ctx, filter).Decode(&menu)
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments) && item.menu.MenuID != "":
+			fail(item.row, "menu %s not found", item.menu.MenuID)
+			continue
+		case errors.Is(err, mongo.ErrNoDocuments):
+			create = true
+			menu.ID = primitive.NewObjectID()
+			menu.MenuID = menu.ID.Hex()
+			menu.CreatedAt = now
+		case err != nil:
+			fail(item.row, "could not look up menu: %v", err)
+			continue
+		}
+
+		if !create {
+			if err := checkDirectMenuEdit(ctx, menu.MenuID); err != nil {
+				fail(item.row, "menu %s: %v", menu.MenuID, err)
+				continue
+			}
+		}
+
+		if item.menu.Name != "" {
+			menu.Name = item.menu.Name
+		}
+		if item.menu.Category != "" {
+			menu.Category = item.menu.Category
+		}
+		menu.UpdatedAt = now
+
+		if validationErr := helpers.Validate.Struct(menu); validationErr != nil {
+			fail(item.row, "%s", validationErr.Error())
+			continue
+		}
+		plannedMenus = append(plannedMenus, plannedMenu{menu: menu, create: create})
+		if create {
+			report.MenusCreated++
+		} else {
+			report.MenusUpdated++
+		}
+
+		seen := map[string]int{}
+		for _, row := range item.foods {
+			if row.parseErr != "" {
+				fail(row.row, "%s", row.parseErr)
+				continue
+			}
+
+			var food models.Food
+			createFood := false
+
+			if row.food.FoodID != "" {
+				err = database.FoodCollection.FindOne(ctx, bson.M{"food_id": row.food.FoodID}).Decode(&food)
+			} else if row.food.Name != nil && !create {
+				err = database.FoodCollection.FindOne(ctx, bson.M{"menu_id": menu.MenuID, "name": *row.food.Name}).Decode(&food)
+			} else {
+				err = mongo.ErrNoDocuments
+			}
+			switch {
+			case errors.Is(err, mongo.ErrNoDocuments) && row.food.FoodID != "":
+				fail(row.row, "food %s not found", row.food.FoodID)
+				continue
+			case errors.Is(err, mongo.ErrNoDocuments):
+				createFood = true
+				food.ID = primitive.NewObjectID()
+				food.FoodID = food.ID.Hex()
+				food.CreatedAt = now
+			case err != nil:
+				fail(row.row, "could not look up food: %v", err)
+				continue
+			case food.MenuID != nil && *food.MenuID != menu.MenuID:
+				// Moving foods between menus is an edit of the food, not something an import row does silently
+				fail(row.row, "food %s belongs to menu %s", food.FoodID, *food.MenuID)
+				continue
+			}
+
+			key := food.FoodID
+			if createFood && row.food.Name != nil {
+				key = "name:" + strings.ToLower(*row.food.Name)
+			}
+			if first, dup := seen[key]; dup {
+				fail(row.row, "duplicate of row %d", first)
+				continue
+			}
+			seen[key] = row.row
+
+			menuID := menu.MenuID
+			food.MenuID = &menuID
+			priceChanged := createFood
+			if row.food.Name != nil {
+				food.Name = row.food.Name
+			}
+			if row.food.Description != nil {
+				food.Description = row.food.Description
+			}
+			if row.food.Price != nil {
+				priceChanged = priceChanged || food.Price == nil || *food.Price != *row.food.Price
+				food.Price = row.food.Price
+			}
+			if row.food.Available != nil {
+				food.Available = row.food.Available
+			}
+			if row.food.Position != nil {
+				food.Position = row.food.Position
+			}
+			if row.food.Image != nil {
+				food.FoodImage = row.food.Image
+			}
+			if row.food.CategoryID != nil {
+				if create {
+					fail(row.row, "category %s cannot belong to a menu that is being created", *row.food.CategoryID)
+					continue
+				}
+				if _, err := findCategory(ctx, *row.food.CategoryID, menu.MenuID); err != nil {
+					fail(row.row, "category %s not found in menu %s", *row.food.CategoryID, menu.MenuID)
+					continue
+				}
+				food.CategoryID = row.food.CategoryID
+			}
+			food.UpdatedAt = now
+
+			if validationErr := helpers.Validate.Struct(food); validationErr != nil {
+				fail(row.row, "%s", validationErr.Error())
+				continue
+			}
+			plannedFoods = append(plannedFoods, plannedFood{food: food, create: createFood, priceChanged: priceChanged})
+			if createFood {
+				report.FoodsCreated++
+			} else {
+				report.FoodsUpdated++
+			}
+		}
+	}
+
+	return plannedMenus, plannedFoods
+}
+
+// applyMenuImport writes the planned menus and foods in two bulk writes and records price changes,
+// all in one transaction so a failing write leaves no half-imported menu behind
+func applyMenuImport(ctx context.Context, menus []plannedMenu, foods []plannedFood, uid string) error {
+	_, err := repository.Transaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if len(menus) > 0 {
+			writes := make([]mongo.WriteModel, 0, len(menus))
+			for _, planned := range menus {
+				writes = append(writes, mongo.NewReplaceOneModel().
+					SetFilter(bson.M{"menu_id": planned.menu.MenuID}).
+					SetReplacement(planned.menu).
+					SetUpsert(true))
+			}
+			if _, err := database.MenuCollection.BulkWrite(sc, writes); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(foods) > 0 {
+			writes := make([]mongo.WriteModel, 0, len(foods))
+			for _, planned := range foods {
+				writes = append(writes, mongo.NewReplaceOneModel().
+					SetFilter(bson.M{"food_id": planned.food.FoodID}).
+					SetReplacement(planned.food).
+					SetUpsert(true))
+			}
+			if _, err := database.FoodCollection.BulkWrite(sc, writes); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, planned := range foods {
+			if !planned.priceChanged {
+				continue
+			}
+			if _, err := recordFoodPrice(sc, planned.food.FoodID, *planned.food.Price, planned.food.UpdatedAt, uid); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// writeMenuCSV renders the flat format; menus without foods get a single menu-only row
+func writeMenuCSV(w io.Writer, menus []MenuTransfer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(menuCSVHeader); err != nil {
+		return err
+	}
+
+	str := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+
+	for _, menu := range menus {
+		if len(menu.Foods) == 0 {
+			if err := writer.Write([]string{menu.MenuID, menu.Name, menu.Category, "", "", "", "", "", "", "", ""}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, food := range menu.Foods {
+			price, available, position := "", "", ""
+			if food.Price != nil {
+				price = food.Price.String()
+			}
+			if food.Available != nil {
+				available = strconv.FormatBool(*food.Available)
+			}
+			if food.Position != nil {
+				position = strconv.Itoa(*food.Position)
+			}
+			record := []string{
+				menu.MenuID, menu.Name, menu.Category,
+				food.FoodID, str(food.Name), str(food.Description), price, available, position, str(food.CategoryID), str(food.Image),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
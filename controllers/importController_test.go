@@ -0,0 +1,32 @@
+package controllers
+
+import "testing"
+
+func TestParseMenuCSVRowErrors(t *testing.T) {
+	data := []byte("menu_name,food_name,price,available,position\n" +
+		"Lunch,Soup,4.50,true,1\n" +
+		"Lunch,Salad,abc,,\n" +
+		"Lunch,Bread,2,maybe,\n" +
+		"Lunch,Water,1,,first\n")
+
+	menus, rows, err := parseMenuCSV(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows != 4 || len(menus) != 1 || len(menus[0].foods) != 4 {
+		t.Fatalf("parsed %d rows into %d menus, want 4 rows in 1 menu", rows, len(menus))
+	}
+
+	want := []string{"", "invalid price abc", "invalid available maybe", "invalid position first"}
+	for i, food := range menus[0].foods {
+		if food.row != i+2 {
+			t.Errorf("food %d has row %d, want %d", i, food.row, i+2)
+		}
+		if food.parseErr != want[i] {
+			t.Errorf("row %d error = %q, want %q", food.row, food.parseErr, want[i])
+		}
+	}
+	if soup := menus[0].foods[0].food; soup.Available == nil || !*soup.Available || soup.Position == nil || *soup.Position != 1 {
+		t.Errorf("valid row parsed as %+v", soup)
+	}
+}
@@ -3,8 +3,12 @@ package controllers
 import (
 	"context"
 	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
 	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,23 +19,42 @@ import (
 
 // Struct to hold invoice view format
 type InvoiceViewFormat struct {
-	InvoiceID      string
-	PaymentMethod  string
-	OrderID        string
-	PaymentStatus  *string
-	PaymentDue     interface{}
-	TableNumber    interface{}
-	PaymentDueDate time.Time
-	OrderDetails   interface{}
+	InvoiceID         string
+	PaymentMethod     string
+	OrderID           string
+	PaymentStatus     *string
+	PaymentDue        money.Money
+	TableNumber       interface{}
+	OrderType         string
+	DeliveryFee       *money.Money
+	PaymentDueDate    time.Time
+	OrderDetails      interface{}
+	NutritionTotal    models.Nutrition
+	NutritionComplete bool
 }
 
-// Get all invoices
+// Get all invoices, optionally filtered by the ?order_type= of their orders (comma separated)
 func GetInvoices() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
 
-		result, err := database.InvoiceCollection.Find(ctx, bson.M{})
+		filter := bson.M{}
+		if v := c.Query("order_type"); v != "" {
+			types, err := orderTypeFilter(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			orderIDs, err := database.OrderCollection.Distinct(ctx, "order_id", bson.M{"type": types})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing invoice items"})
+				return
+			}
+			filter["order_id"] = bson.M{"$in": orderIDs}
+		}
+
+		result, err := database.InvoiceCollection.Find(ctx, filter)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing invoice items"})
 			return
@@ -84,11 +107,38 @@ func GetInvoice() gin.HandlerFunc {
 			invoiceView.PaymentStatus = invoice.PaymentStatus
 		}
 
+		invoiceView.NutritionTotal, invoiceView.NutritionComplete = OrderNutrition(allOrderItems)
+
+		// Delivery orders are charged their fee on top of the items
+		var order models.Order
+		if err := database.OrderCollection.FindOne(ctx, bson.M{"order_id": invoice.OrderID}).Decode(&order); err == nil {
+			invoiceView.OrderType = OrderType(order)
+			if order.Delivery != nil {
+				invoiceView.DeliveryFee = order.Delivery.Fee
+			}
+		}
+
+		cursor, err := database.OrderItemCollection.Find(ctx, bson.M{"order_id": invoice.OrderID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order items"})
+			return
+		}
+		var items []models.OrderItem
+		if err = cursor.All(ctx, &items); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order items"})
+			return
+		}
+		paymentDue, err := orderPaymentDue(items, invoiceView.DeliveryFee)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to total the invoice: " + err.Error()})
+			return
+		}
+		invoiceView.PaymentDue = paymentDue
+
 		if len(allOrderItems) > 0 {
-			invoiceView.PaymentDue = allOrderItems[0]["payment_due"]
 			invoiceView.TableNumber = allOrderItems[0]["table_number"]
-			invoiceView.OrderDetails = allOrderItems[0]["order_items"]
 		}
+		invoiceView.OrderDetails = allOrderItems
 
 		c.JSON(http.StatusOK, invoiceView)
 	}
@@ -136,7 +186,19 @@ func CreateInvoice() gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusCreated, gin.H{"message": "Invoice created successfully", "result": result})
+		publishInvoiceEvent(events.InvoiceCreated, invoice)
+
+		// Issuing the bill moves the table along; a bill that is already paid settles the order
+		response := gin.H{"message": "Invoice created successfully", "result": result}
+		if strings.EqualFold(*invoice.PaymentStatus, "paid") {
+			if err := settleOrder(ctx, order); err != nil {
+				response["warning"] = "Order could not be marked paid: " + err.Error()
+			}
+		} else {
+			advanceTable(ctx, order.TableID, TableBillRequested)
+		}
+
+		c.JSON(http.StatusCreated, response)
 	}
 }
 
@@ -198,6 +260,23 @@ func UpdateInvoice() gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Invoice updated successfully", "result": result})
+		var stored models.Invoice
+		if err := database.InvoiceCollection.FindOne(ctx, filter).Decode(&stored); err == nil {
+			publishInvoiceEvent(events.InvoiceUpdated, stored)
+		}
+
+		// Paying the bill settles the order; once it is out the table is left to be cleaned
+		response := gin.H{"message": "Invoice updated successfully", "result": result}
+		if invoice.PaymentStatus != nil && strings.EqualFold(*invoice.PaymentStatus, "paid") && stored.OrderID != "" {
+			var order models.Order
+			if err := database.OrderCollection.FindOne(ctx, bson.M{"order_id": stored.OrderID}).Decode(&order); err != nil {
+				log.Printf("invoice %s: order %s could not be loaded to mark it paid: %v", invoiceID, stored.OrderID, err)
+				response["warning"] = "Order could not be marked paid: " + err.Error()
+			} else if err := settleOrder(ctx, order); err != nil {
+				response["warning"] = "Order could not be marked paid: " + err.Error()
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
 	}
 }
@@ -0,0 +1,588 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
+	"golang-restaurant-management/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Kitchen ticket and ticket line statuses
+const (
+	TicketOpen   = "open"
+	TicketBumped = "bumped"
+
+	TicketItemCooking = "cooking"
+	TicketItemDone    = "done"
+)
+
+// ErrTicketState is returned when a bump or recall doesn't apply to the ticket or line as it is
+var ErrTicketState = errors.New("kitchen ticket is not in that state")
+
+// Struct to hold a single line of a kitchen ticket
+type KitchenTicketLine struct {
+	OrderItemID string   `json:"order_item_id"`
+	FoodID      string   `json:"food_id"`
+	Name        string   `json:"name"`
+	Size        string   `json:"size,omitempty"`
+	Portions    int      `json:"portions"`
+	Bundle      string   `json:"bundle,omitempty"`
+	Slot        string   `json:"slot,omitempty"`
+	Modifiers   []string `json:"modifiers,omitempty"`
+	StationID   string   `json:"station_id,omitempty"`
+}
+
+// Struct to hold a ticket line with its timer as shown on a station screen
+type KitchenTicketItemView struct {
+	models.KitchenTicketItem
+	ElapsedSeconds int64 `json:"elapsed_seconds"`
+	Late           bool  `json:"late"`
+}
+
+// Struct to hold a ticket with its timers as shown on a station screen
+type KitchenTicketView struct {
+	models.KitchenTicket
+	Items          []KitchenTicketItemView `json:"items"`
+	ElapsedSeconds int64                   `json:"elapsed_seconds"`
+}
+
+// Get the kitchen ticket for an order, with combo meals expanded into their components
+func GetKitchenTicket() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID := c.Param("order_id")
+
+		lines, err := KitchenTicket(orderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while building kitchen ticket"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"order_id": orderID, "lines": lines})
+	}
+}
+
+// Get the queue of a station screen: ?status=open (default) oldest first, or ?status=bumped newest first
+// so recently bumped tickets can be recalled. Pass "unassigned" for foods without a station.
+func GetStationTickets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		stationID := c.Param("station_id")
+		if stationID != KitchenUnassigned && !kitchenStationExists(ctx, stationID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Kitchen station not found"})
+			return
+		}
+
+		status := c.DefaultQuery("status", TicketOpen)
+		opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+		switch status {
+		case TicketOpen:
+		case TicketBumped:
+			opts = options.Find().SetSort(bson.D{{Key: "bumped_at", Value: -1}}).SetLimit(20)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown ticket status: " + status})
+			return
+		}
+
+		tickets, err := findKitchenTickets(ctx, bson.M{"station_id": stationID, "status": status}, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing kitchen tickets"})
+			return
+		}
+
+		c.JSON(http.StatusOK, kitchenTicketViews(ctx, tickets, time.Now()))
+	}
+}
+
+// Get the kitchen tickets of all stations, optionally filtered by ?order_id= and ?status=
+func GetKitchenTickets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{}
+		if orderID := c.Query("order_id"); orderID != "" {
+			filter["order_id"] = orderID
+		}
+		if status := c.Query("status"); status != "" {
+			filter["status"] = status
+		}
+
+		tickets, err := findKitchenTickets(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing kitchen tickets"})
+			return
+		}
+
+		c.JSON(http.StatusOK, kitchenTicketViews(ctx, tickets, time.Now()))
+	}
+}
+
+// Bump a whole ticket: every line still cooking is marked done
+func BumpKitchenTicket() gin.HandlerFunc {
+	return kitchenTicketAction(func(ctx context.Context, c *gin.Context, now time.Time) (*mongo.UpdateResult, error) {
+		return database.KitchenTicketCollection.UpdateOne(ctx,
+			bson.M{"ticket_id": c.Param("ticket_id"), "status": TicketOpen},
+			bson.M{"$set": bson.M{
+				"status":                  TicketBumped,
+				"bumped_at":               now,
+				"updated_at":              now,
+				"items.$[line].status":    TicketItemDone,
+				"items.$[line].bumped_at": now,
+			}},
+			options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"line.status": TicketItemCooking}}}),
+		)
+	})
+}
+
+// Recall a bumped ticket back onto its station screen with every line cooking again. The lines keep their
+// start time, so their timers still show how long the guest has been waiting; the recall is recorded on its own.
+func RecallKitchenTicket() gin.HandlerFunc {
+	return kitchenTicketAction(func(ctx context.Context, c *gin.Context, now time.Time) (*mongo.UpdateResult, error) {
+		return database.KitchenTicketCollection.UpdateOne(ctx,
+			bson.M{"ticket_id": c.Param("ticket_id"), "status": TicketBumped},
+			bson.M{
+				"$set": bson.M{
+					"status":                TicketOpen,
+					"recalled_at":           now,
+					"updated_at":            now,
+					"items.$[].status":      TicketItemCooking,
+					"items.$[].recalled_at": now,
+				},
+				"$unset": bson.M{"bumped_at": "", "items.$[].bumped_at": ""},
+			},
+		)
+	})
+}
+
+// Bump a single line of a ticket; the ticket is bumped with its last line
+func BumpKitchenItem() gin.HandlerFunc {
+	return kitchenTicketAction(func(ctx context.Context, c *gin.Context, now time.Time) (*mongo.UpdateResult, error) {
+		return database.KitchenTicketCollection.UpdateOne(ctx,
+			bson.M{
+				"ticket_id": c.Param("ticket_id"),
+				"items":     bson.M{"$elemMatch": bson.M{"line_id": c.Param("line_id"), "status": TicketItemCooking}},
+			},
+			bson.M{"$set": bson.M{"items.$.status": TicketItemDone, "items.$.bumped_at": now, "updated_at": now}},
+		)
+	})
+}
+
+// Recall a single bumped line; a bumped ticket comes back onto the screen with it
+func RecallKitchenItem() gin.HandlerFunc {
+	return kitchenTicketAction(func(ctx context.Context, c *gin.Context, now time.Time) (*mongo.UpdateResult, error) {
+		return database.KitchenTicketCollection.UpdateOne(ctx,
+			bson.M{
+				"ticket_id": c.Param("ticket_id"),
+				"items":     bson.M{"$elemMatch": bson.M{"line_id": c.Param("line_id"), "status": TicketItemDone}},
+			},
+			bson.M{
+				"$set":   bson.M{"items.$.status": TicketItemCooking, "items.$.recalled_at": now, "updated_at": now},
+				"$unset": bson.M{"items.$.bumped_at": ""},
+			},
+		)
+	})
+}
+
+// kitchenTicketAction wraps a bump or recall update into a handler. The update is conditional on the
+// current state; afterwards the ticket status is settled from its lines and the order is advanced.
+func kitchenTicketAction(update func(ctx context.Context, c *gin.Context, now time.Time) (*mongo.UpdateResult, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		result, err := update(ctx, c, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Kitchen ticket update failed"})
+			return
+		}
+
+		ticket, err := settleKitchenTicket(ctx, c.Param("ticket_id"))
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Kitchen ticket not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Kitchen ticket update failed"})
+			return
+		}
+		if result.MatchedCount == 0 {
+			if c.Param("line_id") != "" && !ticketHasLine(ticket, c.Param("line_id")) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Ticket line not found"})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": ErrTicketState.Error(), "ticket": ticket})
+			return
+		}
+		publishKitchenTicketEvent(events.KitchenTicketUpdated, ticket)
+
+		c.JSON(http.StatusOK, kitchenTicketViews(ctx, []models.KitchenTicket{ticket}, time.Now())[0])
+	}
+}
+
+// fireKitchenTickets routes the order's items that are not yet in the kitchen to their stations,
+// one ticket per station, and returns the tickets it created. Firing is idempotent per order item and station:
+// a combo meal's lines may go to several stations, and a line is skipped once its item is on a ticket of its
+// station (also one of another order it was moved from). The unique index on the tickets' order items and
+// station turns a concurrent firing of the same line into a duplicate key error, after which the remaining
+// lines are planned again.
+func fireKitchenTickets(ctx context.Context, order models.Order) ([]models.KitchenTicket, error) {
+	lines, err := KitchenTicket(order.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := ""
+	for _, line := range lines {
+		if line.StationID == "" {
+			if fallback, err = defaultStationID(ctx); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	created := []models.KitchenTicket{}
+	for attempt := 1; ; attempt++ {
+		fired, err := firedOrderItems(ctx, lines)
+		if err != nil {
+			return created, err
+		}
+
+		tickets := buildKitchenTickets(order, lines, fired, fallback, time.Now())
+		if len(tickets) == 0 {
+			return created, nil
+		}
+
+		docs := make([]interface{}, len(tickets))
+		for i, ticket := range tickets {
+			docs[i] = ticket
+		}
+		_, err = database.KitchenTicketCollection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+		inserted, err := insertedKitchenTickets(tickets, err)
+		for _, ticket := range inserted {
+			publishKitchenTicketEvent(events.KitchenTicketCreated, ticket)
+		}
+		created = append(created, inserted...)
+		if err == nil {
+			return created, nil
+		}
+		if !errors.Is(err, errTicketAlreadyFired) || attempt == 5 {
+			return created, err
+		}
+	}
+}
+
+// errTicketAlreadyFired marks tickets rejected because another firing put one of their items on a ticket first
+var errTicketAlreadyFired = errors.New("kitchen ticket item was already fired")
+
+// firedKey identifies an order item on the tickets of a station
+func firedKey(orderItemID, stationID string) string {
+	return orderItemID + "@" + stationID
+}
+
+// firedOrderItems returns which of the lines' order items are already on a kitchen ticket, keyed by
+// firedKey for every station they are on
+func firedOrderItems(ctx context.Context, lines []KitchenTicketLine) (map[string]bool, error) {
+	ids := bson.A{}
+	for _, line := range lines {
+		ids = append(ids, line.OrderItemID)
+	}
+	fired := map[string]bool{}
+	if len(ids) == 0 {
+		return fired, nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"station_id": 1, "items.order_item_id": 1})
+	cursor, err := database.KitchenTicketCollection.Find(ctx, bson.M{"items.order_item_id": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var tickets []models.KitchenTicket
+	if err = cursor.All(ctx, &tickets); err != nil {
+		return nil, err
+	}
+	for _, ticket := range tickets {
+		for _, item := range ticket.Items {
+			fired[firedKey(item.OrderItemID, ticket.StationID)] = true
+		}
+	}
+	return fired, nil
+}
+
+// buildKitchenTickets groups the lines not fired yet into one ticket per station. Lines of foods without
+// a station go to the fallback station.
+func buildKitchenTickets(order models.Order, lines []KitchenTicketLine, fired map[string]bool, fallback string, now time.Time) []models.KitchenTicket {
+	tickets := []models.KitchenTicket{}
+	byStation := map[string]int{}
+	for _, line := range lines {
+		stationID := line.StationID
+		if stationID == "" {
+			stationID = fallback
+		}
+		if fired[firedKey(line.OrderItemID, stationID)] {
+			continue
+		}
+
+		index, ok := byStation[stationID]
+		if !ok {
+			id := primitive.NewObjectID()
+			tickets = append(tickets, models.KitchenTicket{
+				ID:        id,
+				TicketID:  id.Hex(),
+				OrderID:   order.OrderID,
+				StationID: stationID,
+				TableID:   order.TableID,
+				OrderType: OrderType(order),
+				Status:    TicketOpen,
+				Items:     []models.KitchenTicketItem{},
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+			index = len(tickets) - 1
+			byStation[stationID] = index
+		}
+		tickets[index].Items = append(tickets[index].Items, models.KitchenTicketItem{
+			LineID:      primitive.NewObjectID().Hex(),
+			OrderItemID: line.OrderItemID,
+			FoodID:      line.FoodID,
+			Name:        line.Name,
+			Size:        line.Size,
+			Portions:    line.Portions,
+			Bundle:      line.Bundle,
+			Slot:        line.Slot,
+			Modifiers:   line.Modifiers,
+			Status:      TicketItemCooking,
+			StartedAt:   now,
+		})
+	}
+	return tickets
+}
+
+// insertedKitchenTickets works out from an unordered InsertMany error which tickets were stored. Tickets
+// rejected only as duplicates of already fired items fail with errTicketAlreadyFired; any other error is returned as is.
+func insertedKitchenTickets(tickets []models.KitchenTicket, err error) ([]models.KitchenTicket, error) {
+	if err == nil {
+		return tickets, nil
+	}
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return nil, err
+	}
+
+	rejected := map[int]bool{}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr) {
+			return nil, err
+		}
+		rejected[writeErr.Index] = true
+	}
+	inserted := []models.KitchenTicket{}
+	for i, ticket := range tickets {
+		if !rejected[i] {
+			inserted = append(inserted, ticket)
+		}
+	}
+	return inserted, errTicketAlreadyFired
+}
+
+// settleKitchenTicket brings a ticket's status in line with its lines: it is bumped once every line is done
+// and open again when a line was recalled. When the order has no open tickets left it is marked ready.
+func settleKitchenTicket(ctx context.Context, ticketID string) (models.KitchenTicket, error) {
+	var ticket models.KitchenTicket
+	if err := database.KitchenTicketCollection.FindOne(ctx, bson.M{"ticket_id": ticketID}).Decode(&ticket); err != nil {
+		return ticket, err
+	}
+
+	done := true
+	for _, item := range ticket.Items {
+		if item.Status != TicketItemDone {
+			done = false
+			break
+		}
+	}
+
+	now := time.Now()
+	switch {
+	case done && ticket.Status == TicketOpen:
+		_, err := database.KitchenTicketCollection.UpdateOne(ctx,
+			bson.M{"ticket_id": ticketID, "status": TicketOpen},
+			bson.M{"$set": bson.M{"status": TicketBumped, "bumped_at": now, "updated_at": now}},
+		)
+		if err != nil {
+			return ticket, err
+		}
+		ticket.Status = TicketBumped
+		ticket.BumpedAt = &now
+	case !done && ticket.Status == TicketBumped:
+		_, err := database.KitchenTicketCollection.UpdateOne(ctx,
+			bson.M{"ticket_id": ticketID, "status": TicketBumped},
+			bson.M{"$set": bson.M{"status": TicketOpen, "recalled_at": now, "updated_at": now}, "$unset": bson.M{"bumped_at": ""}},
+		)
+		if err != nil {
+			return ticket, err
+		}
+		ticket.Status = TicketOpen
+		ticket.RecalledAt = &now
+		ticket.BumpedAt = nil
+	}
+
+	if ticket.Status == TicketBumped {
+		open, err := database.KitchenTicketCollection.CountDocuments(ctx, bson.M{"order_id": ticket.OrderID, "status": TicketOpen})
+		if err == nil && open == 0 {
+			advanceOrder(ctx, ticket.OrderID, OrderReady)
+		}
+	}
+	return ticket, nil
+}
+
+// findKitchenTickets loads the tickets matching a filter
+func findKitchenTickets(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.KitchenTicket, error) {
+	cursor, err := database.KitchenTicketCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	tickets := []models.KitchenTicket{}
+	if err = cursor.All(ctx, &tickets); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// kitchenTicketViews adds the timers to tickets: how long each line has been cooking (or took), and whether
+// it is over its station's target time
+func kitchenTicketViews(ctx context.Context, tickets []models.KitchenTicket, now time.Time) []KitchenTicketView {
+	targets := map[string]time.Duration{}
+	cursor, err := database.KitchenStationCollection.Find(ctx, bson.M{"target_minutes": bson.M{"$gt": 0}})
+	if err == nil {
+		var stations []models.KitchenStation
+		if cursor.All(ctx, &stations) == nil {
+			for _, station := range stations {
+				targets[station.StationID] = time.Duration(*station.TargetMinutes) * time.Minute
+			}
+		}
+	}
+
+	views := make([]KitchenTicketView, 0, len(tickets))
+	for _, ticket := range tickets {
+		view := KitchenTicketView{KitchenTicket: ticket, Items: make([]KitchenTicketItemView, 0, len(ticket.Items))}
+		end := now
+		if ticket.BumpedAt != nil {
+			end = *ticket.BumpedAt
+		}
+		view.ElapsedSeconds = int64(end.Sub(ticket.CreatedAt).Seconds())
+
+		target, hasTarget := targets[ticket.StationID]
+		for _, item := range ticket.Items {
+			end := now
+			if item.BumpedAt != nil {
+				end = *item.BumpedAt
+			}
+			elapsed := end.Sub(item.StartedAt)
+			view.Items = append(view.Items, KitchenTicketItemView{
+				KitchenTicketItem: item,
+				ElapsedSeconds:    int64(elapsed.Seconds()),
+				Late:              hasTarget && item.Status == TicketItemCooking && elapsed > target,
+			})
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// ticketHasLine reports whether the ticket contains the given line
+func ticketHasLine(ticket models.KitchenTicket, lineID string) bool {
+	for _, item := range ticket.Items {
+		if item.LineID == lineID {
+			return true
+		}
+	}
+	return false
+}
+
+// KitchenTicket flattens an order's items into the lines the kitchen has to prepare
+func KitchenTicket(orderID string) ([]KitchenTicketLine, error) {
+	orderItems, err := ItemsByOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []KitchenTicketLine{}
+	for _, item := range orderItems {
+		orderItemID := fmt.Sprint(item["order_item_id"])
+		size, _ := item["quantity"].(string)
+		modifiers := documentStrings(item["modifiers"])
+
+		components, isBundle := item["components"].([]bson.M)
+		if !isBundle {
+			foodID, _ := item["food_id"].(string)
+			lines = append(lines, KitchenTicketLine{
+				OrderItemID: orderItemID,
+				FoodID:      foodID,
+				Name:        documentName(item["food"]),
+				Size:        size,
+				Portions:    1,
+				Modifiers:   modifiers,
+				StationID:   documentString(item["food"], "station_id"),
+			})
+			continue
+		}
+
+		bundleName := documentName(item["bundle"])
+		for _, component := range components {
+			foodID, _ := component["food_id"].(string)
+			portions := max(documentInt(component["quantity"]), 1)
+			lines = append(lines, KitchenTicketLine{
+				OrderItemID: orderItemID,
+				FoodID:      foodID,
+				Name:        documentName(component["food"]),
+				Size:        size,
+				Portions:    portions,
+				Bundle:      bundleName,
+				Slot:        fmt.Sprint(component["slot"]),
+				StationID:   documentString(component["food"], "station_id"),
+			})
+		}
+	}
+
+	return lines, nil
+}
+
+// documentName returns the "name" field of a looked-up document, if any
+func documentName(doc interface{}) string {
+	return documentString(doc, "name")
+}
+
+// documentString returns a string field of a looked-up document, if any
+func documentString(doc interface{}, key string) string {
+	if m, ok := doc.(bson.M); ok {
+		if value, ok := m[key].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// documentInt reads a numeric BSON value regardless of its stored width
+func documentInt(value interface{}) int {
+	switch v := value.(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"errors"
+	"golang-restaurant-management/models"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestBuildKitchenTickets(t *testing.T) {
+	order := models.Order{OrderID: "order-1", Type: OrderTakeout}
+	lines := []KitchenTicketLine{
+		{OrderItemID: "burger", Name: "Burger", StationID: "grill"},
+		{OrderItemID: "combo", Name: "Fries", StationID: "fry"},
+		{OrderItemID: "combo", Name: "Steak", StationID: "grill"},
+		{OrderItemID: "salad", Name: "Salad"},
+		{OrderItemID: "fired", Name: "Soup", StationID: "cold"},
+	}
+	now := time.Now()
+
+	tickets := buildKitchenTickets(order, lines, map[string]bool{firedKey("fired", "cold"): true}, "pass", now)
+	items := map[string][]string{}
+	for _, ticket := range tickets {
+		if ticket.OrderID != "order-1" || ticket.Status != TicketOpen || ticket.OrderType != OrderTakeout {
+			t.Errorf("ticket %+v not opened for the order", ticket)
+		}
+		for _, item := range ticket.Items {
+			if !item.StartedAt.Equal(now) || item.Status != TicketItemCooking {
+				t.Errorf("line %s not started cooking", item.Name)
+			}
+			items[ticket.StationID] = append(items[ticket.StationID], item.Name)
+		}
+	}
+
+	if len(tickets) != 3 {
+		t.Fatalf("got %d tickets, want one each for grill, fry and pass", len(tickets))
+	}
+	if got := items["grill"]; len(got) != 2 || got[0] != "Burger" || got[1] != "Steak" {
+		t.Errorf("grill lines = %v", got)
+	}
+	if got := items["pass"]; len(got) != 1 || got[0] != "Salad" {
+		t.Errorf("lines without a station should go to the fallback, got %v", got)
+	}
+	if _, ok := items["cold"]; ok {
+		t.Error("already fired items should not be fired again")
+	}
+
+	fired := map[string]bool{}
+	for _, ticket := range tickets {
+		for _, item := range ticket.Items {
+			fired[firedKey(item.OrderItemID, ticket.StationID)] = true
+		}
+	}
+	fired[firedKey("fired", "cold")] = true
+	if tickets := buildKitchenTickets(order, lines, fired, "pass", now); len(tickets) != 0 {
+		t.Errorf("firing again created %d tickets", len(tickets))
+	}
+}
+
+func TestBuildKitchenTicketsSplitCombo(t *testing.T) {
+	order := models.Order{OrderID: "order-1"}
+	lines := []KitchenTicketLine{
+		{OrderItemID: "combo", Name: "Steak", StationID: "grill"},
+		{OrderItemID: "combo", Name: "Fries", StationID: "fry"},
+		{OrderItemID: "combo", Name: "Drink"},
+	}
+
+	// The grill got its line of the combo; the other stations still need theirs
+	tickets := buildKitchenTickets(order, lines, map[string]bool{firedKey("combo", "grill"): true}, "pass", time.Now())
+	stations := map[string]string{}
+	for _, ticket := range tickets {
+		for _, item := range ticket.Items {
+			stations[ticket.StationID] = item.Name
+		}
+	}
+	if len(tickets) != 2 || stations["fry"] != "Fries" || stations["pass"] != "Drink" {
+		t.Errorf("split combo fired %v, want Fries at fry and Drink at pass", stations)
+	}
+
+	// An item at the fallback station counts as fired there
+	fired := map[string]bool{firedKey("combo", "grill"): true, firedKey("combo", "fry"): true, firedKey("combo", "pass"): true}
+	if tickets := buildKitchenTickets(order, lines, fired, "pass", time.Now()); len(tickets) != 0 {
+		t.Errorf("firing the split combo again created %d tickets", len(tickets))
+	}
+}
+
+func TestInsertedKitchenTickets(t *testing.T) {
+	tickets := []models.KitchenTicket{{TicketID: "a"}, {TicketID: "b"}, {TicketID: "c"}}
+
+	inserted, err := insertedKitchenTickets(tickets, nil)
+	if err != nil || len(inserted) != 3 {
+		t.Errorf("no error: %d inserted (%v)", len(inserted), err)
+	}
+
+	duplicate := mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Index: 1, Code: 11000}}}}
+	inserted, err = insertedKitchenTickets(tickets, duplicate)
+	if !errors.Is(err, errTicketAlreadyFired) || len(inserted) != 2 || inserted[0].TicketID != "a" || inserted[1].TicketID != "c" {
+		t.Errorf("duplicate: %v (%v), want a and c with errTicketAlreadyFired", inserted, err)
+	}
+
+	other := mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Index: 0, Code: 121}}}}
+	if inserted, err = insertedKitchenTickets(tickets, other); errors.Is(err, errTicketAlreadyFired) || len(inserted) != 0 {
+		t.Errorf("other write error: %v (%v), want it returned as is", inserted, err)
+	}
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// KitchenUnassigned is the station ID of tickets for foods without a station when no default station is set
+const KitchenUnassigned = "unassigned"
+
+// Get all kitchen stations by name
+func GetKitchenStations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		result, err := database.KitchenStationCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing kitchen stations"})
+			return
+		}
+
+		stations := []models.KitchenStation{}
+		if err = result.All(ctx, &stations); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing kitchen stations"})
+			return
+		}
+
+		c.JSON(http.StatusOK, stations)
+	}
+}
+
+// Create a kitchen station
+func CreateKitchenStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var station models.KitchenStation
+		if err := c.BindJSON(&station); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(station)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		station.CreatedAt = time.Now()
+		station.UpdatedAt = time.Now()
+		station.ID = primitive.NewObjectID()
+		station.StationID = station.ID.Hex()
+
+		if station.Default != nil && *station.Default {
+			if err := clearDefaultStation(ctx); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Kitchen station was not created"})
+				return
+			}
+		}
+
+		if _, err := database.KitchenStationCollection.InsertOne(ctx, station); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Kitchen station was not created"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, station)
+	}
+}
+
+// Update a kitchen station
+func UpdateKitchenStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var station models.KitchenStation
+		if err := c.BindJSON(&station); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if station.TargetMinutes != nil && *station.TargetMinutes <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "target_minutes must be greater than 0"})
+			return
+		}
+
+		stationID := c.Param("station_id")
+		if !kitchenStationExists(ctx, stationID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Kitchen station not found"})
+			return
+		}
+
+		var updateObj primitive.D
+		if station.Name != nil {
+			updateObj = append(updateObj, bson.E{Key: "name", Value: station.Name})
+		}
+		if station.TargetMinutes != nil {
+			updateObj = append(updateObj, bson.E{Key: "target_minutes", Value: station.TargetMinutes})
+		}
+		if station.Default != nil {
+			// Only one station catches the foods without a station
+			if *station.Default {
+				if err := clearDefaultStation(ctx); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Kitchen station update failed"})
+					return
+				}
+			}
+			updateObj = append(updateObj, bson.E{Key: "default", Value: station.Default})
+		}
+		updateObj = append(updateObj, bson.E{Key: "updated_at", Value: time.Now()})
+
+		result, err := database.KitchenStationCollection.UpdateOne(ctx, bson.M{"station_id": stationID}, bson.D{{Key: "$set", Value: updateObj}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Kitchen station update failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Delete a kitchen station that no food is mapped to and that has no open tickets
+func DeleteKitchenStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		stationID := c.Param("station_id")
+		foods, err := database.FoodCollection.CountDocuments(ctx, bson.M{"station_id": stationID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete kitchen station"})
+			return
+		}
+		tickets, err := database.KitchenTicketCollection.CountDocuments(ctx, bson.M{"station_id": stationID, "status": TicketOpen})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete kitchen station"})
+			return
+		}
+		if foods > 0 || tickets > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Map the station's foods elsewhere and finish its open tickets first"})
+			return
+		}
+
+		result, err := database.KitchenStationCollection.DeleteOne(ctx, bson.M{"station_id": stationID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete kitchen station"})
+			return
+		}
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Kitchen station not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Kitchen station deleted successfully"})
+	}
+}
+
+// kitchenStationExists reports whether a kitchen station with the given ID exists
+func kitchenStationExists(ctx context.Context, stationID string) bool {
+	count, err := database.KitchenStationCollection.CountDocuments(ctx, bson.M{"station_id": stationID})
+	return err == nil && count > 0
+}
+
+// defaultStationID returns the station that receives foods without a station, or KitchenUnassigned
+func defaultStationID(ctx context.Context) (string, error) {
+	var station models.KitchenStation
+	err := database.KitchenStationCollection.FindOne(ctx, bson.M{"default": true}).Decode(&station)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return KitchenUnassigned, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return station.StationID, nil
+}
+
+// clearDefaultStation unmarks the current default station before another one takes over
+func clearDefaultStation(ctx context.Context) error {
+	_, err := database.KitchenStationCollection.UpdateMany(ctx, bson.M{"default": true}, bson.M{"$set": bson.M{"default": false}})
+	return err
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"golang-restaurant-management/database"
 	"golang-restaurant-management/helpers"
 	"golang-restaurant-management/models"
@@ -33,6 +34,11 @@ func GetMenus() gin.HandlerFunc {
 			log.Fatal(err)
 		}
 
+		locale := requestLocale(c)
+		for _, menu := range allMenus {
+			localizeDocument(menu, locale, "name", "category")
+		}
+
 		c.JSON(http.StatusOK, allMenus)
 	}
 }
@@ -52,6 +58,8 @@ func GetMenu() gin.HandlerFunc {
 			return
 		}
 
+		localizeMenu(&menu, requestLocale(c))
+
 		c.JSON(http.StatusOK, menu)
 	}
 }
@@ -112,6 +120,15 @@ func UpdateMenu() gin.HandlerFunc {
 			return
 		}
 
+		if err := checkDirectMenuEdit(ctx, menuId); err != nil {
+			if errors.Is(err, ErrMenuVersioned) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while checking the menu"})
+			return
+		}
+
 		filter := bson.M{"menu_id": menuId}
 		var updateObj primitive.D
 
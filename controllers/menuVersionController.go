@@ -0,0 +1,790 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/repository"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Struct to hold the differences between a draft and the live menu
+type MenuDraftChanges struct {
+	MenuChanged bool                `json:"menu_changed"`
+	Added       []string            `json:"added"`
+	Removed     []string            `json:"removed"`
+	Changed     map[string][]string `json:"changed"`
+}
+
+// StartMenuDraft opens a draft copy of the live menu and its foods (or returns the open draft)
+func StartMenuDraft() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		menuID := c.Param("menu_id")
+
+		draft, err := findMenuDraft(ctx, menuID)
+		if err == nil {
+			c.JSON(http.StatusOK, draft)
+			return
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while loading the draft"})
+			return
+		}
+
+		menu, foods, err := liveMenuSnapshot(ctx, menuID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while copying the menu"})
+			return
+		}
+
+		now := time.Now()
+		draft = models.MenuVersion{
+			ID:        primitive.NewObjectID(),
+			MenuID:    menuID,
+			Status:    "draft",
+			Menu:      menu,
+			Foods:     foods,
+			BaseFoods: foods,
+			CreatedBy: c.GetString("uid"),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		draft.VersionID = draft.ID.Hex()
+
+		if _, err := database.MenuVersionCollection.InsertOne(ctx, draft); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "A draft for this menu was opened concurrently"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Draft could not be created"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, draft)
+	}
+}
+
+// GetMenuDraft returns the open draft of a menu
+func GetMenuDraft() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		draft, err := findMenuDraft(ctx, c.Param("menu_id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No open draft for this menu"})
+			return
+		}
+
+		c.JSON(http.StatusOK, draft)
+	}
+}
+
+// DiscardMenuDraft throws the open draft away
+func DiscardMenuDraft() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		result, err := database.MenuVersionCollection.DeleteOne(ctx, bson.M{"menu_id": c.Param("menu_id"), "status": "draft"})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to discard draft"})
+			return
+		}
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No open draft for this menu"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Draft discarded successfully"})
+	}
+}
+
+// UpdateMenuDraft changes the menu fields of the draft
+func UpdateMenuDraft() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		draft, err := findMenuDraft(ctx, c.Param("menu_id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No open draft for this menu"})
+			return
+		}
+
+		var menu models.Menu
+		if err := c.BindJSON(&menu); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if len(menu.Name) > 0 {
+			draft.Menu.Name = menu.Name
+		}
+		if len(menu.Category) > 0 {
+			draft.Menu.Category = menu.Category
+		}
+		if menu.StartDate != nil && menu.EndDate != nil {
+			if !menu.EndDate.After(*menu.StartDate) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date range"})
+				return
+			}
+			draft.Menu.StartDate = menu.StartDate
+			draft.Menu.EndDate = menu.EndDate
+		}
+		if menu.Translations != nil {
+			if locale := unsupportedTranslationLocale(menu.Translations); locale != "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported locale: " + locale, "supported": helpers.SupportedLocales})
+				return
+			}
+			draft.Menu.Translations = menu.Translations
+		}
+
+		if err := saveMenuDraft(ctx, &draft); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Draft update failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, draft)
+	}
+}
+
+// AddDraftFood adds a new food to the draft; it only becomes visible once published
+func AddDraftFood() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		draft, err := findMenuDraft(ctx, c.Param("menu_id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No open draft for this menu"})
+			return
+		}
+
+		var food models.Food
+		if err := c.BindJSON(&food); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		menuID := draft.MenuID
+		food.MenuID = &menuID
+		if err := NormalizeNutrition(food.Nutrition); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		validationErr := helpers.Validate.Struct(food)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+		if food.CategoryID != nil {
+			if _, err := findCategory(ctx, *food.CategoryID, menuID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found in this menu"})
+				return
+			}
+		}
+
+		food.CreatedAt = time.Now()
+		food.UpdatedAt = time.Now()
+		food.ID = primitive.NewObjectID()
+		food.FoodID = food.ID.Hex()
+		draft.Foods = append(draft.Foods, food)
+
+		if err := saveMenuDraft(ctx, &draft); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Draft update failed"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, food)
+	}
+}
+
+// UpdateDraftFood changes a food inside the draft
+func UpdateDraftFood() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		draft, err := findMenuDraft(ctx, c.Param("menu_id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No open draft for this menu"})
+			return
+		}
+
+		index := draftFoodIndex(draft, c.Param("food_id"))
+		if index < 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food item not found in draft"})
+			return
+		}
+
+		var input models.Food
+		if err := c.BindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		food := draft.Foods[index]
+		if input.Name != nil {
+			food.Name = input.Name
+		}
+		if input.Description != nil {
+			food.Description = input.Description
+		}
+		if input.Price != nil {
+			food.Price = input.Price
+		}
+		if input.FoodImage != nil {
+			food.FoodImage = input.FoodImage
+		}
+		if input.Available != nil {
+			food.Available = input.Available
+		}
+		if input.Position != nil {
+			food.Position = input.Position
+		}
+		if input.CategoryID != nil {
+			if _, err := findCategory(ctx, *input.CategoryID, draft.MenuID); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Category not found in this menu"})
+				return
+			}
+			food.CategoryID = input.CategoryID
+		}
+		if input.Nutrition != nil {
+			if err := NormalizeNutrition(input.Nutrition); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			food.Nutrition = input.Nutrition
+		}
+		if input.SizeFactors != nil {
+			food.SizeFactors = input.SizeFactors
+		}
+		if input.Modifiers != nil {
+			food.Modifiers = input.Modifiers
+		}
+		if input.Translations != nil {
+			if locale := unsupportedTranslationLocale(input.Translations); locale != "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported locale: " + locale, "supported": helpers.SupportedLocales})
+				return
+			}
+			food.Translations = input.Translations
+		}
+
+		validationErr := helpers.Validate.Struct(food)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		food.UpdatedAt = time.Now()
+		draft.Foods[index] = food
+		if err := saveMenuDraft(ctx, &draft); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Draft update failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, food)
+	}
+}
+
+// RemoveDraftFood takes a food off the draft; publishing marks it unavailable
+func RemoveDraftFood() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		draft, err := findMenuDraft(ctx, c.Param("menu_id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No open draft for this menu"})
+			return
+		}
+
+		index := draftFoodIndex(draft, c.Param("food_id"))
+		if index < 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food item not found in draft"})
+			return
+		}
+		draft.Foods = append(draft.Foods[:index], draft.Foods[index+1:]...)
+
+		if err := saveMenuDraft(ctx, &draft); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Draft update failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Food item removed from draft"})
+	}
+}
+
+// PreviewMenuDraft renders the draft as guests would see it, together with what changes against the live menu
+func PreviewMenuDraft() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		menuID := c.Param("menu_id")
+		draft, err := findMenuDraft(ctx, menuID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No open draft for this menu"})
+			return
+		}
+
+		liveMenu, liveFoods, err := liveMenuSnapshot(ctx, menuID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while loading the live menu"})
+			return
+		}
+
+		locale := requestLocale(c)
+		menu := draft.Menu
+		localizeMenu(&menu, locale)
+		foods := make([]models.Food, 0, len(draft.Foods))
+		for _, food := range draft.Foods {
+			if food.Available != nil && !*food.Available {
+				continue
+			}
+			localizeFood(&food, locale)
+			foods = append(foods, food)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"menu":    menu,
+			"foods":   foods,
+			"changes": diffMenuDraft(liveMenu, liveFoods, draft),
+		})
+	}
+}
+
+// PublishMenuDraft atomically makes the draft the live menu
+func PublishMenuDraft() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		draft, err := findMenuDraft(ctx, c.Param("menu_id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No open draft for this menu"})
+			return
+		}
+
+		version, err := publishMenuSnapshot(ctx, draft, c.GetString("uid"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Publishing failed: " + err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Menu published successfully", "version": version})
+	}
+}
+
+// GetMenuVersions lists the published history of a menu, newest first
+func GetMenuVersions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		opts := options.Find().
+			SetSort(bson.D{{Key: "version", Value: -1}}).
+			SetProjection(bson.M{"foods": 0})
+		filter := bson.M{"menu_id": c.Param("menu_id"), "status": bson.M{"$ne": "draft"}}
+
+		result, err := database.MenuVersionCollection.Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing versions"})
+			return
+		}
+
+		versions := []models.MenuVersion{}
+		if err = result.All(ctx, &versions); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing versions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, versions)
+	}
+}
+
+// RollbackMenuVersion republishes an earlier version as a new version
+func RollbackMenuVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		menuID := c.Param("menu_id")
+		number, err := strconv.Atoi(c.Param("version"))
+		if err != nil || number < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version number"})
+			return
+		}
+
+		var target models.MenuVersion
+		filter := bson.M{"menu_id": menuID, "version": number, "status": bson.M{"$ne": "draft"}}
+		if err := database.MenuVersionCollection.FindOne(ctx, filter).Decode(&target); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+			return
+		}
+
+		// The rollback is published as a fresh copy so history stays append-only
+		now := time.Now()
+		snapshot := models.MenuVersion{
+			ID:        primitive.NewObjectID(),
+			MenuID:    menuID,
+			Menu:      target.Menu,
+			Foods:     target.Foods,
+			CreatedBy: c.GetString("uid"),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		snapshot.VersionID = snapshot.ID.Hex()
+
+		version, err := publishMenuSnapshot(ctx, snapshot, c.GetString("uid"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Rollback failed: " + err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Menu rolled back successfully", "version": version, "restored_version": number})
+	}
+}
+
+// publishMenuSnapshot swaps the live menu and foods for the snapshot in one transaction.
+// Live foods missing from the snapshot are marked unavailable rather than deleted, so past orders keep their references.
+// Only the fields the snapshot changed are written, so live edits made since the draft was opened are kept.
+func publishMenuSnapshot(ctx context.Context, snapshot models.MenuVersion, uid string) (int, error) {
+	// Price versions are unique per food. A duplicate key aborts the transaction, so the whole publish is retried.
+	for attempt := 0; ; attempt++ {
+		version, err := publishMenuSnapshotOnce(ctx, snapshot, uid)
+		if mongo.IsDuplicateKeyError(err) && attempt < 5 {
+			continue
+		}
+		return version, err
+	}
+}
+
+// publishMenuSnapshotOnce does the work of publishMenuSnapshot in a single transaction
+func publishMenuSnapshotOnce(ctx context.Context, snapshot models.MenuVersion, uid string) (int, error) {
+	result, err := repository.Transaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		now := time.Now()
+
+		var latest models.MenuVersion
+		opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+		err := database.MenuVersionCollection.FindOne(sc, bson.M{"menu_id": snapshot.MenuID, "version": bson.M{"$gt": 0}}, opts).Decode(&latest)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+		version := latest.Version + 1
+
+		menu := snapshot.Menu
+		menu.PublishedVersion = version
+		menu.PublishedAt = &now
+		menu.UpdatedAt = now
+		if _, err := database.MenuCollection.ReplaceOne(sc, bson.M{"menu_id": snapshot.MenuID}, menu); err != nil {
+			return nil, err
+		}
+
+		bases := map[string]models.Food{}
+		for _, food := range snapshot.BaseFoods {
+			bases[food.FoodID] = food
+		}
+
+		published := map[string]bool{}
+		for _, food := range snapshot.Foods {
+			published[food.FoodID] = true
+
+			// Rollbacks, and drafts opened before their base was recorded, are compared against the live food
+			base, ok := bases[food.FoodID]
+			if !ok {
+				base, err = liveFoodBase(sc, food.FoodID, now)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			set := draftFoodChanges(base, food)
+			if len(set) == 0 {
+				continue
+			}
+			set = append(set, bson.E{Key: "updated_at", Value: now})
+			insert := bson.D{{Key: "_id", Value: food.ID}, {Key: "created_at", Value: now}}
+			if food.StationID != nil {
+				insert = append(insert, bson.E{Key: "station_id", Value: food.StationID})
+			}
+			update := bson.D{{Key: "$set", Value: set}, {Key: "$setOnInsert", Value: insert}}
+			if _, err := database.FoodCollection.UpdateOne(sc, bson.M{"food_id": food.FoodID}, update, options.Update().SetUpsert(true)); err != nil {
+				return nil, err
+			}
+
+			// Price changes go through the price history like any other edit
+			if food.Price != nil && (base.Price == nil || *base.Price != *food.Price) {
+				if _, err := recordFoodPrice(sc, food.FoodID, *food.Price, now, uid); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		ids := make([]string, 0, len(published))
+		for id := range published {
+			ids = append(ids, id)
+		}
+		_, err = database.FoodCollection.UpdateMany(sc,
+			bson.M{"menu_id": snapshot.MenuID, "food_id": bson.M{"$nin": ids}},
+			bson.M{"$set": bson.M{"available": false, "updated_at": now}},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = database.MenuVersionCollection.UpdateMany(sc,
+			bson.M{"menu_id": snapshot.MenuID, "status": "published"},
+			bson.M{"$set": bson.M{"status": "archived", "updated_at": now}},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot.Version = version
+		snapshot.Status = "published"
+		snapshot.Menu = menu
+		snapshot.BaseFoods = nil
+		snapshot.PublishedBy = uid
+		snapshot.PublishedAt = &now
+		snapshot.UpdatedAt = now
+		versionOpts := options.Replace().SetUpsert(true)
+		if _, err := database.MenuVersionCollection.ReplaceOne(sc, bson.M{"version_id": snapshot.VersionID}, snapshot, versionOpts); err != nil {
+			return nil, err
+		}
+
+		return version, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.(int), nil
+}
+
+// liveFoodBase returns the live food with its currently effective price, or an empty food when it doesn't exist yet
+func liveFoodBase(ctx context.Context, foodID string, at time.Time) (models.Food, error) {
+	var food models.Food
+	err := database.FoodCollection.FindOne(ctx, bson.M{"food_id": foodID}).Decode(&food)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.Food{}, nil
+	}
+	if err != nil {
+		return food, err
+	}
+
+	price, err := FoodPriceAt(ctx, foodID, at)
+	if err == nil {
+		food.Price = &price
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return food, err
+	}
+	return food, nil
+}
+
+// draftFoodChanges lists the fields owned by the draft workflow that differ between the base food and the draft.
+// The kitchen station and uploaded thumbnails aren't owned by drafts and are never part of it.
+func draftFoodChanges(base, food models.Food) bson.D {
+	fields := []struct {
+		key         string
+		base, draft interface{}
+	}{
+		{"name", base.Name, food.Name},
+		{"description", base.Description, food.Description},
+		{"price", base.Price, food.Price},
+		{"food_image", base.FoodImage, food.FoodImage},
+		{"menu_id", base.MenuID, food.MenuID},
+		{"category_id", base.CategoryID, food.CategoryID},
+		{"position", base.Position, food.Position},
+		{"available", base.Available, food.Available},
+		{"nutrition", base.Nutrition, food.Nutrition},
+		{"size_factors", base.SizeFactors, food.SizeFactors},
+		{"modifiers", base.Modifiers, food.Modifiers},
+		{"translations", base.Translations, food.Translations},
+	}
+
+	var set bson.D
+	for _, field := range fields {
+		if !reflect.DeepEqual(field.base, field.draft) {
+			set = append(set, bson.E{Key: field.key, Value: field.draft})
+		}
+	}
+	return set
+}
+
+// liveMenuSnapshot copies the live menu and its foods, with foods carrying their currently effective price
+func liveMenuSnapshot(ctx context.Context, menuID string) (models.Menu, []models.Food, error) {
+	var menu models.Menu
+	if err := database.MenuCollection.FindOne(ctx, bson.M{"menu_id": menuID}).Decode(&menu); err != nil {
+		return menu, nil, err
+	}
+
+	pipeline := append(mongo.Pipeline{{{Key: "$match", Value: bson.M{"menu_id": menuID}}}}, currentPriceStages(time.Now())...)
+	cursor, err := database.FoodCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return menu, nil, err
+	}
+	foods := []models.Food{}
+	if err := cursor.All(ctx, &foods); err != nil {
+		return menu, nil, err
+	}
+
+	return menu, foods, nil
+}
+
+// diffMenuDraft lists which foods a publish would add, remove or change
+func diffMenuDraft(liveMenu models.Menu, liveFoods []models.Food, draft models.MenuVersion) MenuDraftChanges {
+	changes := MenuDraftChanges{Added: []string{}, Removed: []string{}, Changed: map[string][]string{}}
+	changes.MenuChanged = liveMenu.Name != draft.Menu.Name || liveMenu.Category != draft.Menu.Category
+
+	live := map[string]models.Food{}
+	for _, food := range liveFoods {
+		if food.Available == nil || *food.Available {
+			live[food.FoodID] = food
+		}
+	}
+
+	for _, food := range draft.Foods {
+		before, ok := live[food.FoodID]
+		delete(live, food.FoodID)
+		if !ok {
+			changes.Added = append(changes.Added, food.FoodID)
+			continue
+		}
+
+		var fields []string
+		if strValue(before.Name) != strValue(food.Name) {
+			fields = append(fields, "name")
+		}
+		if strValue(before.Description) != strValue(food.Description) {
+			fields = append(fields, "description")
+		}
+		if (before.Price == nil) != (food.Price == nil) || (before.Price != nil && *before.Price != *food.Price) {
+			fields = append(fields, "price")
+		}
+		if strValue(before.FoodImage) != strValue(food.FoodImage) {
+			fields = append(fields, "image")
+		}
+		if strValue(before.CategoryID) != strValue(food.CategoryID) {
+			fields = append(fields, "category_id")
+		}
+		if food.Available != nil && !*food.Available {
+			fields = append(fields, "available")
+		}
+		if len(fields) > 0 {
+			changes.Changed[food.FoodID] = fields
+		}
+	}
+
+	for id := range live {
+		changes.Removed = append(changes.Removed, id)
+	}
+
+	return changes
+}
+
+// checkDirectFoodEdit applies checkDirectMenuEdit to the menu of a food
+func checkDirectFoodEdit(ctx context.Context, foodID string) error {
+	var food models.Food
+	if err := database.FoodCollection.FindOne(ctx, bson.M{"food_id": foodID}).Decode(&food); err != nil {
+		return err
+	}
+	if food.MenuID == nil {
+		return nil
+	}
+	return checkDirectMenuEdit(ctx, *food.MenuID)
+}
+
+// directEditError maps errors of checkDirectMenuEdit and checkDirectFoodEdit to responses
+func directEditError(c *gin.Context, err error, label string) {
+	switch {
+	case errors.Is(err, ErrMenuVersioned):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, gin.H{"error": label + " not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while checking the menu"})
+	}
+}
+
+// ErrMenuVersioned is returned for direct edits to a menu that is published or has an open draft
+var ErrMenuVersioned = errors.New("menu is published or has an open draft; edit the draft and publish it instead")
+
+// checkDirectMenuEdit rejects direct edits to a menu whose changes have to go through a draft:
+// once published the live menu only changes on publish, and an open draft would silently overwrite the edit
+func checkDirectMenuEdit(ctx context.Context, menuID string) error {
+	var menu models.Menu
+	err := database.MenuCollection.FindOne(ctx, bson.M{"menu_id": menuID}).Decode(&menu)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	if menu.PublishedVersion > 0 {
+		return ErrMenuVersioned
+	}
+	drafts, err := database.MenuVersionCollection.CountDocuments(ctx, bson.M{"menu_id": menuID, "status": "draft"})
+	if err != nil {
+		return err
+	}
+	if drafts > 0 {
+		return ErrMenuVersioned
+	}
+	return nil
+}
+
+// findMenuDraft loads the open draft of a menu
+func findMenuDraft(ctx context.Context, menuID string) (models.MenuVersion, error) {
+	var draft models.MenuVersion
+	err := database.MenuVersionCollection.FindOne(ctx, bson.M{"menu_id": menuID, "status": "draft"}).Decode(&draft)
+	return draft, err
+}
+
+// saveMenuDraft writes the draft's menu and foods back
+func saveMenuDraft(ctx context.Context, draft *models.MenuVersion) error {
+	draft.UpdatedAt = time.Now()
+	_, err := database.MenuVersionCollection.UpdateOne(ctx,
+		bson.M{"version_id": draft.VersionID, "status": "draft"},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "menu", Value: draft.Menu},
+			{Key: "foods", Value: draft.Foods},
+			{Key: "updated_at", Value: draft.UpdatedAt},
+		}}},
+	)
+	return err
+}
+
+// draftFoodIndex returns the position of a food in the draft, or -1
+func draftFoodIndex(draft models.MenuVersion, foodID string) int {
+	for i, food := range draft.Foods {
+		if food.FoodID == foodID {
+			return i
+		}
+	}
+	return -1
+}
+
+// strValue dereferences an optional string
+func strValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
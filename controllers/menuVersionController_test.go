@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"reflect"
+	"testing"
+)
+
+func TestDraftFoodChanges(t *testing.T) {
+	name, renamed := "Margherita", "Margherita DOC"
+	station, grill := "pizza", "grill"
+	unavailable := false
+	price, raised := money.New(900, "USD"), money.New(1100, "USD")
+	base := models.Food{FoodID: "food-1", Name: &name, Price: &price, StationID: &station}
+
+	keys := func(food models.Food) []string {
+		var keys []string
+		for _, e := range draftFoodChanges(base, food) {
+			keys = append(keys, e.Key)
+		}
+		return keys
+	}
+
+	tests := []struct {
+		name  string
+		draft models.Food
+		want  []string
+	}{
+		{"unchanged", base, nil},
+		{"renamed", models.Food{FoodID: "food-1", Name: &renamed, Price: &price, StationID: &station}, []string{"name"}},
+		{"repriced", models.Food{FoodID: "food-1", Name: &name, Price: &raised, StationID: &station}, []string{"price"}},
+		{"taken off", models.Food{FoodID: "food-1", Name: &name, Price: &price, StationID: &station, Available: &unavailable}, []string{"available"}},
+		{"station is not owned by drafts", models.Food{FoodID: "food-1", Name: &name, Price: &price, StationID: &grill}, nil},
+		{"translations", models.Food{FoodID: "food-1", Name: &name, Price: &price, StationID: &station,
+			Translations: map[string]models.Translation{"es": {}}}, []string{"translations"}},
+	}
+	for _, tt := range tests {
+		if got := keys(tt.draft); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: changed %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// A new food has no base, so everything it sets is written
+	if got := draftFoodChanges(models.Food{}, base); len(got) != 2 {
+		t.Errorf("new food: changed %v, want name and price", got)
+	}
+}
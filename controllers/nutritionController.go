@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/models"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Portion multipliers used for foods that don't define their own size_factors
+var DefaultSizeFactors = map[string]float64{"S": 0.75, "M": 1, "L": 1.25}
+
+// GetFoodNutrition previews a food's nutrition for ?size= and comma separated ?modifiers=
+func GetFoodNutrition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var food models.Food
+		if err := database.FoodCollection.FindOne(ctx, bson.M{"food_id": c.Param("food_id")}).Decode(&food); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food item not found"})
+			return
+		}
+
+		size := c.DefaultQuery("size", "M")
+		var modifiers []string
+		if v := c.Query("modifiers"); v != "" {
+			modifiers = strings.Split(v, ",")
+		}
+
+		nutrition, err := FoodNutrition(food, size, modifiers)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if nutrition == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No nutritional information for this food item"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"food_id": food.FoodID, "size": size, "modifiers": modifiers, "nutrition": nutrition})
+	}
+}
+
+// GetOrderNutrition returns the nutrition of every order line and the order total
+func GetOrderNutrition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID := c.Param("order_id")
+
+		allOrderItems, err := ItemsByOrder(orderID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing order items"})
+			return
+		}
+
+		lines := []gin.H{}
+		for _, item := range allOrderItems {
+			lines = append(lines, gin.H{"order_item_id": item["order_item_id"], "nutrition": item["nutrition"]})
+		}
+		total, complete := OrderNutrition(allOrderItems)
+
+		c.JSON(http.StatusOK, gin.H{"order_id": orderID, "total": total, "complete": complete, "lines": lines})
+	}
+}
+
+// NormalizeNutrition derives per-portion values from per-100g values (or the other way round) using the portion weight
+func NormalizeNutrition(facts *models.NutritionFacts) error {
+	if facts == nil {
+		return nil
+	}
+	if facts.PerPortion == nil && facts.Per100g == nil {
+		return errors.New("nutrition needs per_portion or per_100g values")
+	}
+	if facts.PortionGrams <= 0 {
+		if facts.PerPortion == nil {
+			return errors.New("portion_grams is required to derive per-portion values")
+		}
+		return nil
+	}
+	if facts.PerPortion == nil {
+		perPortion := facts.Per100g.Scale(facts.PortionGrams / 100)
+		facts.PerPortion = &perPortion
+	}
+	if facts.Per100g == nil {
+		per100g := facts.PerPortion.Scale(100 / facts.PortionGrams)
+		facts.Per100g = &per100g
+	}
+	return nil
+}
+
+// FoodNutrition returns the nutrition of one portion of food in the given size with modifiers applied.
+// It returns nil when the food has no nutritional information.
+func FoodNutrition(food models.Food, size string, modifiers []string) (*models.Nutrition, error) {
+	factor, ok := food.SizeFactors[size]
+	if !ok {
+		factor, ok = DefaultSizeFactors[size]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown size %q", size)
+	}
+
+	chosen, err := chosenModifiers(food, modifiers)
+	if err != nil {
+		return nil, err
+	}
+	if food.Nutrition == nil || food.Nutrition.PerPortion == nil {
+		return nil, nil
+	}
+
+	nutrition := *food.Nutrition.PerPortion
+	for _, modifier := range chosen {
+		nutrition = nutrition.Add(modifier.Nutrition)
+	}
+	nutrition = roundNutrition(nutrition.NonNegative().Scale(factor))
+	return &nutrition, nil
+}
+
+// chosenModifiers resolves modifier names against the food's modifiers
+func chosenModifiers(food models.Food, names []string) ([]models.FoodModifier, error) {
+	chosen := make([]models.FoodModifier, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, modifier := range food.Modifiers {
+			if strings.EqualFold(modifier.Name, name) {
+				chosen = append(chosen, modifier)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown modifier %q for %s", name, food.FoodID)
+		}
+	}
+	return chosen, nil
+}
+
+// attachNutrition sets "nutrition" on every order item returned by ItemsByOrder; bundle lines sum their components.
+// Lines whose foods lack nutritional information get a nil value.
+func attachNutrition(orderItems []bson.M) {
+	for _, item := range orderItems {
+		size, _ := item["quantity"].(string)
+		modifiers := documentStrings(item["modifiers"])
+
+		if components, ok := item["components"].([]bson.M); ok {
+			var total models.Nutrition
+			complete := len(components) > 0
+			for _, component := range components {
+				var food models.Food
+				if decodeDocument(component["food"], &food) != nil {
+					complete = false
+					break
+				}
+				nutrition, err := FoodNutrition(food, size, nil)
+				if err != nil || nutrition == nil {
+					complete = false
+					break
+				}
+				total = total.Add(nutrition.Scale(float64(max(documentInt(component["quantity"]), 1))))
+			}
+			if complete {
+				item["nutrition"] = roundNutrition(total)
+			} else {
+				item["nutrition"] = nil
+			}
+			continue
+		}
+
+		var food models.Food
+		if decodeDocument(item["food"], &food) != nil {
+			item["nutrition"] = nil
+			continue
+		}
+		nutrition, err := FoodNutrition(food, size, modifiers)
+		if err != nil || nutrition == nil {
+			item["nutrition"] = nil
+			continue
+		}
+		item["nutrition"] = *nutrition
+	}
+}
+
+// OrderNutrition adds up the nutrition of all order items; complete is false if any line lacks information
+func OrderNutrition(orderItems []bson.M) (models.Nutrition, bool) {
+	var total models.Nutrition
+	complete := true
+	for _, item := range orderItems {
+		nutrition, ok := item["nutrition"].(models.Nutrition)
+		if !ok {
+			complete = false
+			continue
+		}
+		total = total.Add(nutrition)
+	}
+	return roundNutrition(total), complete
+}
+
+// roundNutrition keeps label values to one decimal place
+func roundNutrition(n models.Nutrition) models.Nutrition {
+	round := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return models.Nutrition{
+		EnergyKcal:     round(n.EnergyKcal),
+		ProteinG:       round(n.ProteinG),
+		CarbohydratesG: round(n.CarbohydratesG),
+		SugarsG:        round(n.SugarsG),
+		FatG:           round(n.FatG),
+		SaturatedFatG:  round(n.SaturatedFatG),
+		FiberG:         round(n.FiberG),
+		SaltG:          round(n.SaltG),
+	}
+}
+
+// decodeDocument converts a raw looked-up document into a model
+func decodeDocument(doc interface{}, out interface{}) error {
+	m, ok := doc.(bson.M)
+	if !ok {
+		return errors.New("not a document")
+	}
+	data, err := bson.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, out)
+}
+
+// documentStrings reads a BSON array of strings
+func documentStrings(value interface{}) []string {
+	arr, _ := value.(bson.A)
+	values := make([]string, 0, len(arr))
+	for _, v := range arr {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"golang-restaurant-management/models"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFoodNutritionClampsModifiersAtZero(t *testing.T) {
+	food := models.Food{
+		Nutrition: &models.NutritionFacts{PerPortion: &models.Nutrition{EnergyKcal: 500, FatG: 20, SugarsG: 4}},
+		Modifiers: []models.FoodModifier{
+			{Name: "no sauce", Nutrition: models.Nutrition{EnergyKcal: -120, SugarsG: -6}},
+			{Name: "extra cheese", Nutrition: models.Nutrition{EnergyKcal: 80, FatG: 7}},
+		},
+	}
+
+	got, err := FoodNutrition(food, "M", []string{"no sauce"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (models.Nutrition{EnergyKcal: 380, FatG: 20}); *got != want {
+		t.Errorf("no sauce = %+v, want %+v", *got, want)
+	}
+
+	got, err = FoodNutrition(food, "M", []string{"no sauce", "extra cheese"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (models.Nutrition{EnergyKcal: 460, FatG: 27}); *got != want {
+		t.Errorf("no sauce, extra cheese = %+v, want %+v", *got, want)
+	}
+
+	if _, err := FoodNutrition(food, "M", []string{"no bun"}); err == nil {
+		t.Error("unknown modifier accepted")
+	}
+}
+
+func TestOrderNutrition(t *testing.T) {
+	items := []bson.M{
+		{"nutrition": models.Nutrition{EnergyKcal: 380.04, FatG: 20}},
+		{"nutrition": models.Nutrition{EnergyKcal: 120, FatG: 0.5}},
+	}
+	total, complete := OrderNutrition(items)
+	if want := (models.Nutrition{EnergyKcal: 500, FatG: 20.5}); total != want || !complete {
+		t.Errorf("total = %+v (complete %v), want %+v", total, complete, want)
+	}
+
+	_, complete = OrderNutrition(append(items, bson.M{"nutrition": nil}))
+	if complete {
+		t.Error("order with a line lacking nutrition reported complete")
+	}
+}
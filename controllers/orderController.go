@@ -2,9 +2,13 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
 	"golang-restaurant-management/helpers"
 	"golang-restaurant-management/models"
+	"golang-restaurant-management/repository"
 	"net/http"
 	"strconv"
 	"time"
@@ -16,7 +20,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// Get all orders with pagination
+// Get all orders with pagination, optionally filtered by ?status= and ?type= (comma separated)
 func GetOrders() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
@@ -34,7 +38,25 @@ func GetOrders() gin.HandlerFunc {
 		}
 
 		startIndex := (page - 1) * recordPerPage
-		matchStage := bson.D{{Key: "$match", Value: bson.D{}}}
+
+		filter := bson.D{}
+		if v := c.Query("status"); v != "" {
+			statuses, err := orderStatusFilter(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			filter = append(filter, bson.E{Key: "status", Value: statuses})
+		}
+		if v := c.Query("type"); v != "" {
+			types, err := orderTypeFilter(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			filter = append(filter, bson.E{Key: "type", Value: types})
+		}
+		matchStage := bson.D{{Key: "$match", Value: filter}}
 		skipStage := bson.D{{Key: "$skip", Value: startIndex}}
 		limitStage := bson.D{{Key: "$limit", Value: recordPerPage}}
 
@@ -80,7 +102,6 @@ func CreateOrder() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
 
-		var table models.Table
 		var order models.Order
 
 		// Parse JSON body
@@ -101,13 +122,21 @@ func CreateOrder() gin.HandlerFunc {
 			return
 		}
 
-		// Check if table exists
+		// Each order type needs its own details: a table, a pickup or a delivery
+		order.Type = OrderType(order)
+		if err := validateOrderType(order); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Check if table exists; orders for a merged table go to the combined one
 		if order.TableID != nil {
-			err := database.TableCollection.FindOne(ctx, bson.M{"table_id": order.TableID}).Decode(&table)
+			table, err := resolveTable(ctx, *order.TableID)
 			if err != nil {
 				c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
 				return
 			}
+			order.TableID = &table.TableID
 		}
 
 		// Assign timestamps and IDs
@@ -115,6 +144,7 @@ func CreateOrder() gin.HandlerFunc {
 		order.UpdatedAt = time.Now()
 		order.ID = primitive.NewObjectID()
 		order.OrderID = order.ID.Hex()
+		initOrderStatus(&order, c.GetString("uid"))
 
 		// Insert into database
 		result, insertErr := database.OrderCollection.InsertOne(ctx, order)
@@ -123,6 +153,11 @@ func CreateOrder() gin.HandlerFunc {
 			return
 		}
 
+		publishOrderEvent(events.OrderCreated, order)
+
+		// Walk-in orders seat the table on the way
+		advanceTable(ctx, order.TableID, TableSeated, TableOrdered)
+
 		c.JSON(http.StatusOK, result)
 	}
 }
@@ -133,7 +168,6 @@ func UpdateOrder() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
 
-		var table models.Table
 		var order models.Order
 
 		orderID := c.Param("order_id")
@@ -153,15 +187,80 @@ func UpdateOrder() gin.HandlerFunc {
 
 		// Prepare update object
 		var updateObj primitive.D
+		var unsetObj primitive.D
 
-		// Validate if table exists before updating
-		if order.TableID != nil {
-			err := database.TableCollection.FindOne(ctx, bson.M{"table_id": order.TableID}).Decode(&table)
-			if err != nil {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+		var existing models.Order
+		err := database.OrderCollection.FindOne(ctx, bson.M{"order_id": orderID}).Decode(&existing)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Order update failed"})
+			return
+		}
+
+		// The type and its details are checked on the order as it will be stored
+		if order.Type != "" || order.TableID != nil || order.Customer != nil || order.PickupAt != nil || order.Delivery != nil {
+			merged := existing
+			if order.Type != "" && order.Type != OrderType(existing) {
+				// Switching type drops the details of the old one
+				merged.Type = order.Type
+				merged.TableID, merged.PickupAt, merged.Delivery = nil, nil, nil
+			}
+			merged.Type = OrderType(merged)
+			if merged.Type != OrderDineIn && order.TableID != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Only dine_in orders have a table_id"})
 				return
 			}
-			updateObj = append(updateObj, bson.E{Key: "table_id", Value: order.TableID})
+
+			var table models.Table
+			if order.TableID != nil {
+				table, err = resolveTable(ctx, *order.TableID)
+				if err != nil {
+					c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+					return
+				}
+				merged.TableID = &table.TableID
+			}
+			if order.Customer != nil {
+				merged.Customer = order.Customer
+			}
+			if order.PickupAt != nil {
+				merged.PickupAt = order.PickupAt
+			}
+			if order.Delivery != nil {
+				merged.Delivery = order.Delivery
+			}
+			if err := validateOrderType(merged); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+
+			// Moving an existing dine-in order to another table is a transfer and goes into the history
+			if order.TableID != nil && existing.OrderID != "" && OrderType(existing) == OrderDineIn &&
+				(existing.TableID == nil || *existing.TableID != table.TableID) {
+				if _, err := moveOrder(ctx, existing, table, nil, c.GetString("uid")); err != nil {
+					if errors.Is(err, ErrTransfer) {
+						c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+						return
+					}
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Order could not be transferred"})
+					return
+				}
+			}
+
+			order.TableID = merged.TableID
+			updateObj = append(updateObj, bson.E{Key: "type", Value: merged.Type}, bson.E{Key: "table_id", Value: merged.TableID})
+			if merged.Customer != nil {
+				updateObj = append(updateObj, bson.E{Key: "customer", Value: merged.Customer})
+			}
+			if merged.PickupAt != nil {
+				updateObj = append(updateObj, bson.E{Key: "pickup_at", Value: merged.PickupAt})
+			} else {
+				unsetObj = append(unsetObj, bson.E{Key: "pickup_at", Value: ""})
+			}
+			if merged.Delivery != nil {
+				updateObj = append(updateObj, bson.E{Key: "delivery", Value: merged.Delivery})
+			} else {
+				unsetObj = append(unsetObj, bson.E{Key: "delivery", Value: ""})
+			}
 		}
 
 		// Update timestamp
@@ -173,32 +272,53 @@ func UpdateOrder() gin.HandlerFunc {
 		upsert := true
 		opt := options.UpdateOptions{Upsert: &upsert}
 
-		result, err := database.OrderCollection.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: updateObj}}, &opt)
+		update := bson.D{{Key: "$set", Value: updateObj}}
+		if len(unsetObj) > 0 {
+			update = append(update, bson.E{Key: "$unset", Value: unsetObj})
+		}
+		result, err := database.OrderCollection.UpdateOne(ctx, filter, update, &opt)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Order update failed"})
 			return
 		}
 
+		publishOrderChange(ctx, events.OrderUpdated, orderID)
+		advanceTable(ctx, order.TableID, TableSeated, TableOrdered)
+
 		c.JSON(http.StatusOK, result)
 	}
 }
 
-// OrderItemOrderCreator - Creates an order and returns its OrderID
-func OrderItemOrderCreator(order models.Order) string {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-	defer cancel()
+// ErrOrderNotCreated is returned when an order could not be stored
+var ErrOrderNotCreated = errors.New("order could not be created")
 
+// OrderItemOrderCreator creates an order and returns it. IDs already assigned to the order are kept, so
+// callers can reference the order before it is stored. Pass the transaction's session context to make the
+// order part of a larger transaction; the caller is then responsible for advancing the table after commit.
+func OrderItemOrderCreator(ctx context.Context, order models.Order, uid string) (models.Order, error) {
 	// Assign timestamps and IDs
 	order.CreatedAt = time.Now()
 	order.UpdatedAt = time.Now()
-	order.ID = primitive.NewObjectID()
-	order.OrderID = order.ID.Hex()
+	if order.OrderID == "" {
+		order.ID = primitive.NewObjectID()
+		order.OrderID = order.ID.Hex()
+	}
+	order.Type = OrderType(order)
+	initOrderStatus(&order, uid)
+
+	// Orders for a merged table go to the combined one
+	if order.TableID != nil {
+		table, err := resolveTable(ctx, *order.TableID)
+		if err != nil {
+			return order, fmt.Errorf("%w: table %s not found", ErrOrderNotCreated, *order.TableID)
+		}
+		order.TableID = &table.TableID
+	}
 
 	// Insert into database
-	_, err := database.OrderCollection.InsertOne(ctx, order)
-	if err != nil {
-		return ""
+	if err := repository.Orders().Insert(ctx, &order); err != nil {
+		return order, fmt.Errorf("%w: %w", ErrOrderNotCreated, err)
 	}
 
-	return order.OrderID
+	return order, nil
 }
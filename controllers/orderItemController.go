@@ -2,9 +2,14 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
 	"golang-restaurant-management/helpers"
 	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"golang-restaurant-management/repository"
 	"net/http"
 	"time"
 
@@ -18,6 +23,10 @@ import (
 // Struct to hold order items
 type OrderItemPack struct {
 	TableID    *string
+	Type       string                `json:"type"`
+	Customer   *models.OrderCustomer `json:"customer"`
+	PickupAt   *time.Time            `json:"pickup_at"`
+	Delivery   *models.OrderDelivery `json:"delivery"`
 	OrderItems []models.OrderItem
 }
 
@@ -43,7 +52,7 @@ func GetOrderItems() gin.HandlerFunc {
 	}
 }
 
-// Get order items by Order ID
+// Get order items by Order ID, with the nutrition total of the whole order
 func GetOrderItemsByOrder() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		orderID := c.Param("order_id")
@@ -54,7 +63,12 @@ func GetOrderItemsByOrder() gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, allOrderItems)
+		total, complete := OrderNutrition(allOrderItems)
+		c.JSON(http.StatusOK, gin.H{
+			"order_id":    orderID,
+			"order_items": allOrderItems,
+			"nutrition":   gin.H{"total": total, "complete": complete},
+		})
 	}
 }
 
@@ -104,6 +118,20 @@ func UpdateOrderItem() gin.HandlerFunc {
 		if orderItem.FoodID != nil {
 			updateObj = append(updateObj, bson.E{Key: "food_id", Value: *orderItem.FoodID})
 		}
+		if orderItem.Modifiers != nil {
+			updateObj = append(updateObj, bson.E{Key: "modifiers", Value: orderItem.Modifiers})
+		}
+
+		// Re-price a swapped food or changed modifiers at the order's date unless a price was given explicitly
+		if (orderItem.FoodID != nil || orderItem.Modifiers != nil) && orderItem.UnitPrice == nil {
+			price, rule, err := orderItemPriceAtOrderDate(ctx, orderItemID, orderItem.FoodID, orderItem.Modifiers)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Could not resolve price for food item: " + err.Error()})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "unit_price", Value: price})
+			updateObj = append(updateObj, bson.E{Key: "pricing_rule_id", Value: ruleID(rule)})
+		}
 
 		// Update timestamp
 		orderItem.UpdatedAt = time.Now()
@@ -121,11 +149,17 @@ func UpdateOrderItem() gin.HandlerFunc {
 			return
 		}
 
+		var updated models.OrderItem
+		if err := database.OrderItemCollection.FindOne(ctx, filter).Decode(&updated); err == nil {
+			publishOrderItemEvent(ctx, events.OrderItemUpdated, updated)
+		}
+
 		c.JSON(http.StatusOK, result)
 	}
 }
 
-// Create order items
+// Create an order together with its items. Nothing is stored unless the order and every item are valid:
+// the items are priced and validated first, then the order and items are inserted in one transaction.
 func CreateOrderItem() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
@@ -139,23 +173,97 @@ func CreateOrderItem() gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if len(orderItemPack.OrderItems) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "At least one order item is required"})
+			return
+		}
 
-		// Assign timestamps
+		// The order's ID is assigned up front so the items can reference it before anything is stored
 		order.OrderDate = time.Now()
+		order.ID = primitive.NewObjectID()
+		order.OrderID = order.ID.Hex()
 		order.TableID = orderItemPack.TableID
-		orderID := OrderItemOrderCreator(order)
+		order.Type = orderItemPack.Type
+		order.Customer = orderItemPack.Customer
+		order.PickupAt = orderItemPack.PickupAt
+		order.Delivery = orderItemPack.Delivery
+		order.Type = OrderType(order)
+		if err := helpers.Validate.Struct(order); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := validateOrderType(order); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if orderItemPack.TableID != nil {
+			table, err := resolveTable(ctx, *orderItemPack.TableID)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+				return
+			}
+			order.TableID = &table.TableID
+		}
 
 		// Prepare order items for insertion
-		orderItemsToBeInserted := []interface{}{}
+		orderItemsToBeInserted := []models.OrderItem{}
 
 		for _, orderItem := range orderItemPack.OrderItems {
-			orderItem.OrderID = orderID
-
-			// Validate input
-			validationErr := helpers.Validate.Struct(orderItem)
-			if validationErr != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
-				return
+			orderItem.OrderID = order.OrderID
+
+			// Combo meals are priced from the bundle and its chosen substitutions
+			if orderItem.BundleID != nil {
+				var bundle models.Bundle
+				err := database.BundleCollection.FindOne(ctx, bson.M{"bundle_id": *orderItem.BundleID}).Decode(&bundle)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Bundle not found: " + *orderItem.BundleID})
+					return
+				}
+
+				available, err := bundleFoodAvailability(ctx, bundle)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while checking bundle availability"})
+					return
+				}
+
+				price, selections, err := ResolveBundleSelections(bundle, orderItem.Selections, available)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				orderItem.FoodID = nil
+				orderItem.UnitPrice = &price
+				orderItem.Selections = selections
+			} else if orderItem.FoodID != nil {
+				var food models.Food
+				if err := database.FoodCollection.FindOne(ctx, bson.M{"food_id": *orderItem.FoodID}).Decode(&food); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Food item not found: " + *orderItem.FoodID})
+					return
+				}
+				if err := checkFoodAvailable(food); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+
+				// Charge the price that was effective when the order was placed, adjusted by any pricing rule running then
+				price, err := FoodPriceAt(ctx, *orderItem.FoodID, order.OrderDate)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Food item not found: " + *orderItem.FoodID})
+					return
+				}
+				price, rule, err := ApplyPricingRules(ctx, food, price, order.OrderDate)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while applying pricing rules"})
+					return
+				}
+				orderItem.PricingRuleID = ruleID(rule)
+
+				// Chosen modifiers must exist on the food and add their upcharge
+				if price, err = addModifierUpcharges(food, price, orderItem.Modifiers); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				orderItem.UnitPrice = &price
 			}
 
 			// Assign IDs and timestamps
@@ -164,21 +272,43 @@ func CreateOrderItem() gin.HandlerFunc {
 			orderItem.CreatedAt = time.Now()
 			orderItem.UpdatedAt = time.Now()
 
-			// Round unit price
-			var num = toFixed(*orderItem.UnitPrice, 2)
-			orderItem.UnitPrice = &num
+			// Validate input
+			validationErr := helpers.Validate.Struct(orderItem)
+			if validationErr != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+				return
+			}
 
 			orderItemsToBeInserted = append(orderItemsToBeInserted, orderItem)
 		}
 
-		// Insert into DB
-		insertedOrderItems, err := database.OrderItemCollection.InsertMany(ctx, orderItemsToBeInserted)
+		// Insert the order and its items atomically
+		result, err := repository.Transaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+			created, err := OrderItemOrderCreator(sc, order, c.GetString("uid"))
+			if err != nil {
+				return nil, err
+			}
+			order = created
+			return repository.OrderItems().InsertMany(sc, orderItemsToBeInserted)
+		})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert order items"})
+			if errors.Is(err, ErrOrderNotCreated) {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Order could not be created, no items were stored"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert order items, no order was created"})
 			return
 		}
 
-		c.JSON(http.StatusOK, insertedOrderItems)
+		publishOrderEvent(events.OrderCreated, order)
+		for _, orderItem := range orderItemsToBeInserted {
+			publishOrderItemEvent(ctx, events.OrderItemCreated, orderItem)
+		}
+
+		// Walk-in orders seat the table on the way
+		advanceTable(ctx, order.TableID, TableSeated, TableOrdered)
+
+		c.JSON(http.StatusOK, result)
 	}
 }
 
@@ -213,5 +343,169 @@ func ItemsByOrder(orderID string) ([]bson.M, error) {
 		return nil, err
 	}
 
+	if err = expandBundleLines(ctx, orderItems); err != nil {
+		return nil, err
+	}
+	attachNutrition(orderItems)
+
 	return orderItems, nil
 }
+
+// expandBundleLines attaches the bundle and its component food lines to every combo meal order item
+func expandBundleLines(ctx context.Context, orderItems []bson.M) error {
+	bundleIDs := []string{}
+	foodIDs := []string{}
+	for _, item := range orderItems {
+		bundleID, ok := item["bundle_id"].(string)
+		if !ok {
+			continue
+		}
+		bundleIDs = append(bundleIDs, bundleID)
+		selections, _ := item["bundle_selections"].(bson.A)
+		for _, s := range selections {
+			if selection, ok := s.(bson.M); ok {
+				if foodID, ok := selection["food_id"].(string); ok {
+					foodIDs = append(foodIDs, foodID)
+				}
+			}
+		}
+	}
+	if len(bundleIDs) == 0 {
+		return nil
+	}
+
+	bundles, err := findByKey(ctx, database.BundleCollection, "bundle_id", bundleIDs)
+	if err != nil {
+		return err
+	}
+	foods, err := findByKey(ctx, database.FoodCollection, "food_id", foodIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range orderItems {
+		bundleID, ok := item["bundle_id"].(string)
+		if !ok {
+			continue
+		}
+		item["bundle"] = bundles[bundleID]
+
+		components := []bson.M{}
+		selections, _ := item["bundle_selections"].(bson.A)
+		for _, s := range selections {
+			selection, ok := s.(bson.M)
+			if !ok {
+				continue
+			}
+			foodID, _ := selection["food_id"].(string)
+			components = append(components, bson.M{
+				"slot":     selection["slot"],
+				"food_id":  foodID,
+				"quantity": selection["quantity"],
+				"upcharge": selection["upcharge"],
+				"food":     foods[foodID],
+			})
+		}
+		item["components"] = components
+	}
+
+	return nil
+}
+
+// findByKey loads the documents whose key is in values, indexed by that key
+func findByKey(ctx context.Context, collection *mongo.Collection, key string, values []string) (map[string]bson.M, error) {
+	docs := map[string]bson.M{}
+	if len(values) == 0 {
+		return docs, nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{key: bson.M{"$in": values}})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	for _, doc := range results {
+		if id, ok := doc[key].(string); ok {
+			docs[id] = doc
+		}
+	}
+
+	return docs, nil
+}
+
+// orderItemPriceAtOrderDate re-prices an existing item at the date of its order, pricing rules and modifier
+// upcharges included. A nil food or modifier list keeps the item's current one; a new food has to be available.
+func orderItemPriceAtOrderDate(ctx context.Context, orderItemID string, foodID *string, modifiers []string) (money.Money, *models.PricingRule, error) {
+	var orderItem models.OrderItem
+	if err := database.OrderItemCollection.FindOne(ctx, bson.M{"order_item_id": orderItemID}).Decode(&orderItem); err != nil {
+		return money.Money{}, nil, err
+	}
+	if modifiers == nil {
+		modifiers = orderItem.Modifiers
+	}
+	swapped := foodID != nil
+	if !swapped {
+		foodID = orderItem.FoodID
+	}
+	if foodID == nil {
+		return money.Money{}, nil, errors.New("order item has no food item")
+	}
+
+	var order models.Order
+	if err := database.OrderCollection.FindOne(ctx, bson.M{"order_id": orderItem.OrderID}).Decode(&order); err != nil {
+		return money.Money{}, nil, err
+	}
+
+	var food models.Food
+	if err := database.FoodCollection.FindOne(ctx, bson.M{"food_id": *foodID}).Decode(&food); err != nil {
+		return money.Money{}, nil, err
+	}
+	if swapped {
+		if err := checkFoodAvailable(food); err != nil {
+			return money.Money{}, nil, err
+		}
+	}
+
+	price, err := FoodPriceAt(ctx, *foodID, order.OrderDate)
+	if err != nil {
+		return money.Money{}, nil, err
+	}
+	price, rule, err := ApplyPricingRules(ctx, food, price, order.OrderDate)
+	if err != nil {
+		return money.Money{}, nil, err
+	}
+	price, err = addModifierUpcharges(food, price, modifiers)
+	return price, rule, err
+}
+
+// addModifierUpcharges adds the upcharges of the chosen modifiers to a price. The modifiers must exist on the food.
+func addModifierUpcharges(food models.Food, price money.Money, names []string) (money.Money, error) {
+	if len(names) == 0 {
+		return price, nil
+	}
+	modifiers, err := chosenModifiers(food, names)
+	if err != nil {
+		return money.Money{}, err
+	}
+	for _, modifier := range modifiers {
+		if modifier.Upcharge.IsZero() {
+			continue
+		}
+		if price, err = price.Add(modifier.Upcharge); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return price, nil
+}
+
+// checkFoodAvailable rejects foods that can't currently be ordered, such as those publishing took off a menu
+func checkFoodAvailable(food models.Food) error {
+	if food.Available != nil && !*food.Available {
+		return fmt.Errorf("food %s is not available", food.FoodID)
+	}
+	return nil
+}
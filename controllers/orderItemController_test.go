@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"testing"
+)
+
+func TestAddModifierUpcharges(t *testing.T) {
+	food := models.Food{FoodID: "burger", Modifiers: []models.FoodModifier{
+		{Name: "extra cheese", Upcharge: money.New(150, "USD")},
+		{Name: "no bun"},
+	}}
+	base := money.New(1000, "USD")
+
+	price, err := addModifierUpcharges(food, base, []string{"Extra Cheese", "no bun"})
+	if err != nil || price != money.New(1150, "USD") {
+		t.Errorf("with modifiers: %v %v, want 11.50", price, err)
+	}
+	if price, err := addModifierUpcharges(food, base, nil); err != nil || price != base {
+		t.Errorf("without modifiers: %v %v, want 10.00", price, err)
+	}
+	if _, err := addModifierUpcharges(food, base, []string{"bacon"}); err == nil {
+		t.Error("unknown modifier accepted")
+	}
+}
+
+func TestCheckFoodAvailable(t *testing.T) {
+	yes, no := true, false
+	for _, tt := range []struct {
+		available *bool
+		ok        bool
+	}{{nil, true}, {&yes, true}, {&no, false}} {
+		if err := checkFoodAvailable(models.Food{FoodID: "food-1", Available: tt.available}); (err == nil) != tt.ok {
+			t.Errorf("available %v: %v, want ok=%v", tt.available, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
+	"golang-restaurant-management/models"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Order lifecycle statuses
+const (
+	OrderOpen      = "open"
+	OrderSubmitted = "submitted"
+	OrderInKitchen = "in_kitchen"
+	OrderReady     = "ready"
+	OrderServed    = "served"
+	OrderPaid      = "paid"
+	OrderClosed    = "closed"
+	OrderCancelled = "cancelled"
+)
+
+// orderTransitions lists the statuses each status may move to. Payment is tracked apart from the status
+// (see settleOrder): orders paid up front keep moving through the kitchen and only become paid once out.
+var orderTransitions = map[string][]string{
+	OrderOpen:      {OrderSubmitted, OrderCancelled},
+	OrderSubmitted: {OrderInKitchen, OrderOpen, OrderCancelled},
+	OrderInKitchen: {OrderReady, OrderCancelled},
+	OrderReady:     {OrderServed, OrderPaid},
+	OrderServed:    {OrderSubmitted, OrderPaid},
+	OrderPaid:      {OrderClosed},
+	OrderClosed:    {},
+	OrderCancelled: {},
+}
+
+// finishedOrderStatuses are the statuses of orders that no longer occupy a table
+var finishedOrderStatuses = bson.A{OrderPaid, OrderClosed, OrderCancelled}
+
+// ErrInvalidOrderTransition is returned when an order can't move from its current status to the requested one
+var ErrInvalidOrderTransition = errors.New("invalid order status transition")
+
+// Struct to hold a requested order status change
+type OrderStatusPack struct {
+	Status string `json:"status" validate:"required"`
+}
+
+// UpdateOrderStatus moves an order to a new status if the lifecycle allows it
+func UpdateOrderStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var pack OrderStatusPack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if _, ok := orderTransitions[pack.Status]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown order status: " + pack.Status})
+			return
+		}
+
+		order, err := TransitionOrder(ctx, c.Param("order_id"), pack.Status, c.GetString("uid"))
+		if err != nil {
+			switch {
+			case errors.Is(err, mongo.ErrNoDocuments):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			case errors.Is(err, ErrInvalidOrderTransition):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "allowed": orderTransitions[OrderStatus(order)]})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Order status update failed"})
+			}
+			return
+		}
+
+		// Submitted items go to their kitchen stations; the order is in the kitchen once a ticket is fired.
+		// Submitting again fires only the items that are not on a ticket yet.
+		if order.Status == OrderSubmitted {
+			tickets, err := fireKitchenTickets(ctx, order)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Order was submitted but its kitchen tickets could not be created, submit it again"})
+				return
+			}
+			if len(tickets) > 0 {
+				if moved, err := TransitionOrder(ctx, order.OrderID, OrderInKitchen, ""); err == nil {
+					order = moved
+				}
+			}
+		}
+
+		// An order paid up front is finished once it has been served
+		if order.Status == OrderServed && order.PaidAt != nil {
+			if paid, err := finishPaidOrder(ctx, order); err == nil {
+				order = paid
+			}
+		}
+
+		// A cancelled order may leave its table empty
+		if order.Status == OrderCancelled && order.TableID != nil {
+			if open, err := openOrders(ctx, *order.TableID); err == nil && len(open) == 0 {
+				advanceTable(ctx, order.TableID, TableNeedsCleaning)
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"order": order, "allowed": orderTransitions[order.Status]})
+	}
+}
+
+// TransitionOrder moves an order to the given status, failing with ErrInvalidOrderTransition when the
+// lifecycle does not allow it. Like TransitionTable the update is conditional on the status that was read,
+// and each change is appended to the order's status history. The returned order reflects the stored state.
+func TransitionOrder(ctx context.Context, orderID, to, by string) (models.Order, error) {
+	var order models.Order
+	if err := database.OrderCollection.FindOne(ctx, bson.M{"order_id": orderID}).Decode(&order); err != nil {
+		return order, err
+	}
+
+	from := OrderStatus(order)
+	if from == to {
+		return order, nil
+	}
+	if !canTransitionOrder(from, to) {
+		return order, fmt.Errorf("%w: %s -> %s", ErrInvalidOrderTransition, from, to)
+	}
+
+	now := time.Now()
+	change := models.OrderStatusChange{From: from, To: to, At: now, By: by}
+	filter := bson.M{"order_id": orderID, "status": order.Status}
+	if order.Status == "" {
+		// Orders created before the lifecycle existed have no status field
+		filter["status"] = bson.M{"$in": bson.A{nil, ""}}
+	}
+	update := bson.M{
+		"$set":  bson.M{"status": to, "status_updated_at": now, "updated_at": now},
+		"$push": bson.M{"status_history": change},
+	}
+
+	result, err := database.OrderCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return order, err
+	}
+	if result.MatchedCount == 0 {
+		return order, fmt.Errorf("%w: order status changed concurrently", ErrInvalidOrderTransition)
+	}
+
+	order.Status = to
+	order.StatusUpdatedAt = &now
+	order.StatusHistory = append(order.StatusHistory, change)
+	order.UpdatedAt = now
+	publishOrderEvent(events.OrderStatusChanged, order)
+	return order, nil
+}
+
+// advanceOrder applies the automatic transitions triggered elsewhere (payments, the kitchen), walking
+// through the given steps in order. Steps the order's current status doesn't allow are skipped; every
+// failed step is logged and the first failure returned, so callers can report it.
+func advanceOrder(ctx context.Context, orderID string, steps ...string) error {
+	if orderID == "" {
+		return nil
+	}
+	var failed error
+	for _, to := range steps {
+		_, err := TransitionOrder(ctx, orderID, to, "")
+		if err == nil {
+			continue
+		}
+		log.Printf("order %s: automatic transition to %s failed: %v", orderID, to, err)
+		if failed == nil {
+			failed = err
+		}
+		if !errors.Is(err, ErrInvalidOrderTransition) {
+			break
+		}
+	}
+	return failed
+}
+
+// settleOrder records that an order's bill has been paid. An order that is ready or served is finished
+// straight away; one paid up front keeps its status and is finished once it has been served.
+func settleOrder(ctx context.Context, order models.Order) error {
+	if order.PaidAt == nil {
+		now := time.Now()
+		result, err := database.OrderCollection.UpdateOne(ctx,
+			bson.M{"order_id": order.OrderID, "paid_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"paid_at": now, "updated_at": now}},
+		)
+		if err != nil {
+			return err
+		}
+		order.PaidAt = &now
+		order.UpdatedAt = now
+		if result.ModifiedCount > 0 {
+			publishOrderEvent(events.OrderUpdated, order)
+		}
+	}
+
+	switch OrderStatus(order) {
+	case OrderReady, OrderServed:
+		_, err := finishPaidOrder(ctx, order)
+		return err
+	}
+	return nil
+}
+
+// finishPaidOrder moves a paid order that is out of the kitchen to paid and leaves its table to be cleaned
+func finishPaidOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	paid, err := TransitionOrder(ctx, order.OrderID, OrderPaid, "")
+	if err != nil {
+		log.Printf("order %s: automatic transition to %s failed: %v", order.OrderID, OrderPaid, err)
+		return order, err
+	}
+	advanceTable(ctx, paid.TableID, TableBillRequested, TableNeedsCleaning)
+	return paid, nil
+}
+
+// initOrderStatus starts a new order's lifecycle as open and unpaid
+func initOrderStatus(order *models.Order, by string) {
+	now := order.CreatedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	order.PaidAt = nil
+	order.Status = OrderOpen
+	order.StatusUpdatedAt = &now
+	order.StatusHistory = []models.OrderStatusChange{{To: OrderOpen, At: now, By: by}}
+}
+
+// OrderStatus returns the status of an order, treating orders without one as open
+func OrderStatus(order models.Order) string {
+	if order.Status == "" {
+		return OrderOpen
+	}
+	return order.Status
+}
+
+// canTransitionOrder reports whether the lifecycle allows moving from one status to another
+func canTransitionOrder(from, to string) bool {
+	for _, allowed := range orderTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import "testing"
+
+func TestCanTransitionOrder(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{OrderOpen, OrderSubmitted, true},
+		{OrderSubmitted, OrderInKitchen, true},
+		{OrderInKitchen, OrderReady, true},
+		{OrderReady, OrderServed, true},
+		{OrderServed, OrderSubmitted, true},
+		{OrderPaid, OrderClosed, true},
+		{OrderOpen, OrderPaid, false},
+		{OrderSubmitted, OrderPaid, false},
+		{OrderInKitchen, OrderPaid, false},
+		{OrderReady, OrderPaid, true},
+		{OrderServed, OrderPaid, true},
+		{OrderCancelled, OrderPaid, false},
+		{OrderClosed, OrderPaid, false},
+		{OrderPaid, OrderCancelled, false},
+		{OrderOpen, OrderReady, false},
+		{"unknown", OrderOpen, false},
+	}
+	for _, tt := range tests {
+		if got := canTransitionOrder(tt.from, tt.to); got != tt.want {
+			t.Errorf("%s -> %s = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestOrdersOnlyFinishOnceOut(t *testing.T) {
+	// Orders paid up front keep moving through the kitchen, so paid is only reachable once the food is out
+	for from := range orderTransitions {
+		out := from == OrderReady || from == OrderServed
+		if canTransitionOrder(from, OrderPaid) != out {
+			t.Errorf("%s -> paid = %v, want %v", from, !out, out)
+		}
+	}
+}
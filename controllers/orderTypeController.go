@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Order types
+const (
+	OrderDineIn   = "dine_in"
+	OrderTakeout  = "takeout"
+	OrderDelivery = "delivery"
+)
+
+// orderTypes lists every known order type
+var orderTypes = []string{OrderDineIn, OrderTakeout, OrderDelivery}
+
+// ErrInvalidOrderType is returned when an order lacks the details its type needs, or has details of another type
+var ErrInvalidOrderType = errors.New("invalid order for its type")
+
+// Struct to hold the totals of one order type in the order summary
+type OrderTypeSummary struct {
+	Type        string                 `json:"type"`
+	Orders      int                    `json:"orders"`
+	Items       int                    `json:"items"`
+	ItemTotal   map[string]money.Money `json:"item_total"`   // Item revenue per currency
+	DeliveryFee map[string]money.Money `json:"delivery_fee"` // Delivery fees per currency
+	Total       map[string]money.Money `json:"total"`        // Items and delivery fees per currency
+}
+
+// GetOrderSummary reports order counts and revenue per order type for ?from= to ?to= (RFC3339, default the last 24 hours),
+// optionally limited to ?type= and ?status= (comma separated)
+func GetOrderSummary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		to := time.Now()
+		from := to.Add(-24 * time.Hour)
+		for name, target := range map[string]*time.Time{"from": &from, "to": &to} {
+			if v := c.Query(name); v != "" {
+				parsed, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + " timestamp, expected RFC3339"})
+					return
+				}
+				*target = parsed
+			}
+		}
+
+		filter := bson.M{"order_date": bson.M{"$gte": from, "$lt": to}}
+		if v := c.Query("type"); v != "" {
+			types, err := orderTypeFilter(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			filter["type"] = types
+		}
+		if v := c.Query("status"); v != "" {
+			statuses, err := orderStatusFilter(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			filter["status"] = statuses
+		}
+
+		cursor, err := database.OrderCollection.Find(ctx, filter, options.Find().SetProjection(bson.M{"status_history": 0}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while building the order summary"})
+			return
+		}
+		var orders []models.Order
+		if err = cursor.All(ctx, &orders); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while building the order summary"})
+			return
+		}
+
+		summaries := map[string]*OrderTypeSummary{}
+		for _, orderType := range orderTypes {
+			summaries[orderType] = &OrderTypeSummary{Type: orderType, ItemTotal: map[string]money.Money{}, DeliveryFee: map[string]money.Money{}, Total: map[string]money.Money{}}
+		}
+
+		typeOf := map[string]string{}
+		orderIDs := []string{}
+		for _, order := range orders {
+			summary := summaries[OrderType(order)]
+			summary.Orders++
+			typeOf[order.OrderID] = summary.Type
+			orderIDs = append(orderIDs, order.OrderID)
+			if order.Delivery != nil && order.Delivery.Fee != nil {
+				addByCurrency(summary.DeliveryFee, *order.Delivery.Fee)
+			}
+		}
+
+		if len(orderIDs) > 0 {
+			cursor, err = database.OrderItemCollection.Find(ctx, bson.M{"order_id": bson.M{"$in": orderIDs}})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while building the order summary"})
+				return
+			}
+			var items []models.OrderItem
+			if err = cursor.All(ctx, &items); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while building the order summary"})
+				return
+			}
+			for _, item := range items {
+				summary := summaries[typeOf[item.OrderID]]
+				summary.Items++
+				if item.UnitPrice != nil {
+					addByCurrency(summary.ItemTotal, *item.UnitPrice)
+				}
+			}
+		}
+
+		report := []OrderTypeSummary{}
+		for _, orderType := range orderTypes {
+			summary := summaries[orderType]
+			for _, amount := range summary.ItemTotal {
+				addByCurrency(summary.Total, amount)
+			}
+			for _, amount := range summary.DeliveryFee {
+				addByCurrency(summary.Total, amount)
+			}
+			report = append(report, *summary)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"from": from, "to": to, "types": report})
+	}
+}
+
+// validateOrderType checks that an order carries exactly the details its type needs:
+// a table for dine-in, a customer and pickup time for takeout, an address and fee for delivery
+func validateOrderType(order models.Order) error {
+	switch OrderType(order) {
+	case OrderDineIn:
+		if order.TableID == nil || *order.TableID == "" {
+			return fmt.Errorf("%w: dine_in orders need a table_id", ErrInvalidOrderType)
+		}
+		if order.PickupAt != nil || order.Delivery != nil {
+			return fmt.Errorf("%w: dine_in orders have no pickup_at or delivery", ErrInvalidOrderType)
+		}
+	case OrderTakeout:
+		if order.Customer == nil || order.PickupAt == nil {
+			return fmt.Errorf("%w: takeout orders need a customer and pickup_at", ErrInvalidOrderType)
+		}
+		if order.TableID != nil || order.Delivery != nil {
+			return fmt.Errorf("%w: takeout orders have no table_id or delivery", ErrInvalidOrderType)
+		}
+	case OrderDelivery:
+		if order.Delivery == nil {
+			return fmt.Errorf("%w: delivery orders need a delivery address and fee", ErrInvalidOrderType)
+		}
+		if order.Delivery.Fee != nil && order.Delivery.Fee.Amount < 0 {
+			return fmt.Errorf("%w: delivery fee can't be negative", ErrInvalidOrderType)
+		}
+		if order.TableID != nil || order.PickupAt != nil {
+			return fmt.Errorf("%w: delivery orders have no table_id or pickup_at", ErrInvalidOrderType)
+		}
+	default:
+		return fmt.Errorf("%w: unknown order type %s", ErrInvalidOrderType, order.Type)
+	}
+	return nil
+}
+
+// OrderType returns the type of an order, treating orders without one as dine-in
+func OrderType(order models.Order) string {
+	if order.Type == "" {
+		return OrderDineIn
+	}
+	return order.Type
+}
+
+// orderTypeFilter turns a comma separated list of order types into a query condition
+func orderTypeFilter(v string) (bson.M, error) {
+	types := bson.A{}
+	for _, orderType := range strings.Split(v, ",") {
+		switch orderType {
+		case OrderDineIn:
+			// Orders without a type are dine-in
+			types = append(types, OrderDineIn, nil, "")
+		case OrderTakeout, OrderDelivery:
+			types = append(types, orderType)
+		default:
+			return nil, errors.New("Unknown order type: " + orderType)
+		}
+	}
+	return bson.M{"$in": types}, nil
+}
+
+// orderStatusFilter turns a comma separated list of order statuses into a query condition
+func orderStatusFilter(v string) (bson.M, error) {
+	statuses := bson.A{}
+	for _, status := range strings.Split(v, ",") {
+		if _, ok := orderTransitions[status]; !ok {
+			return nil, errors.New("Unknown order status: " + status)
+		}
+		statuses = append(statuses, status)
+		if status == OrderOpen {
+			// Orders without a status are open
+			statuses = append(statuses, nil, "")
+		}
+	}
+	return bson.M{"$in": statuses}, nil
+}
+
+// orderPaymentDue totals what an order costs: the price of every item plus the delivery fee, if any.
+// An order without prices is due zero in the default currency.
+func orderPaymentDue(items []models.OrderItem, deliveryFee *money.Money) (money.Money, error) {
+	amounts := []money.Money{}
+	if deliveryFee != nil {
+		amounts = append(amounts, *deliveryFee)
+	}
+	for _, item := range items {
+		if item.UnitPrice != nil {
+			amounts = append(amounts, *item.UnitPrice)
+		}
+	}
+	if len(amounts) == 0 {
+		return money.Zero(money.DefaultCurrency()), nil
+	}
+
+	due := amounts[0]
+	for _, amount := range amounts[1:] {
+		var err error
+		if due, err = due.Add(amount); err != nil {
+			return due, err
+		}
+	}
+	return due, nil
+}
+
+// addByCurrency adds an amount to the running total of its currency
+func addByCurrency(totals map[string]money.Money, amount money.Money) {
+	total, ok := totals[amount.Currency]
+	if !ok {
+		totals[amount.Currency] = amount
+		return
+	}
+	// Same currency by construction, so Add can't fail
+	totals[amount.Currency], _ = total.Add(amount)
+}
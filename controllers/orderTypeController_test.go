@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"errors"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"testing"
+	"time"
+)
+
+func TestValidateOrderType(t *testing.T) {
+	table := "table-1"
+	address := "12 Harbour Street"
+	now := time.Now()
+	fee := money.New(350, "USD")
+	negative := money.New(-1, "USD")
+	customer := &models.OrderCustomer{}
+
+	tests := []struct {
+		name  string
+		order models.Order
+		valid bool
+	}{
+		{"dine-in by default", models.Order{TableID: &table}, true},
+		{"dine-in without table", models.Order{Type: OrderDineIn}, false},
+		{"dine-in with delivery", models.Order{TableID: &table, Delivery: &models.OrderDelivery{Address: &address, Fee: &fee}}, false},
+		{"takeout", models.Order{Type: OrderTakeout, Customer: customer, PickupAt: &now}, true},
+		{"takeout without pickup time", models.Order{Type: OrderTakeout, Customer: customer}, false},
+		{"takeout at a table", models.Order{Type: OrderTakeout, Customer: customer, PickupAt: &now, TableID: &table}, false},
+		{"delivery", models.Order{Type: OrderDelivery, Delivery: &models.OrderDelivery{Address: &address, Fee: &fee}}, true},
+		{"delivery without details", models.Order{Type: OrderDelivery}, false},
+		{"delivery with negative fee", models.Order{Type: OrderDelivery, Delivery: &models.OrderDelivery{Address: &address, Fee: &negative}}, false},
+		{"delivery with pickup time", models.Order{Type: OrderDelivery, Delivery: &models.OrderDelivery{Address: &address, Fee: &fee}, PickupAt: &now}, false},
+		{"unknown type", models.Order{Type: "drive_through"}, false},
+	}
+	for _, tt := range tests {
+		err := validateOrderType(tt.order)
+		if tt.valid && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidOrderType) {
+			t.Errorf("%s: %v, want ErrInvalidOrderType", tt.name, err)
+		}
+	}
+}
+
+func TestOrderPaymentDue(t *testing.T) {
+	price := func(amount int64) *money.Money {
+		m := money.New(amount, "USD")
+		return &m
+	}
+	items := []models.OrderItem{{UnitPrice: price(1200)}, {UnitPrice: price(850)}, {}}
+
+	due, err := orderPaymentDue(items, price(300))
+	if err != nil || due.Amount != 2350 {
+		t.Errorf("with delivery fee: %v (%v), want 2350", due, err)
+	}
+	due, err = orderPaymentDue(items, nil)
+	if err != nil || due.Amount != 2050 {
+		t.Errorf("without delivery fee: %v (%v), want 2050", due, err)
+	}
+	euro := money.New(100, "EUR")
+	if _, err := orderPaymentDue(items, &euro); err == nil {
+		t.Error("mixed currencies should fail")
+	}
+	due, err = orderPaymentDue(nil, nil)
+	if err != nil || !due.IsZero() {
+		t.Errorf("empty order: %v (%v), want zero", due, err)
+	}
+}
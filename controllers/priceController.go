@@ -0,0 +1,314 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"golang-restaurant-management/cache"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetFoodPrices lists the price history of a food item, including scheduled prices.
+// With ?at=<RFC3339> it also reports the price effective at that moment.
+func GetFoodPrices() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		foodID := c.Param("food_id")
+
+		at := time.Now()
+		if v := c.Query("at"); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid at timestamp, expected RFC3339"})
+				return
+			}
+			at = parsed
+		}
+
+		effective, err := FoodPriceAt(ctx, foodID, at)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Food item not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while resolving price"})
+			return
+		}
+
+		opts := options.Find().SetSort(bson.D{{Key: "effective_at", Value: -1}})
+		result, err := database.FoodPriceCollection.Find(ctx, bson.M{"food_id": foodID}, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing prices"})
+			return
+		}
+
+		history := []models.FoodPrice{}
+		if err = result.All(ctx, &history); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing prices"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"food_id":         foodID,
+			"at":              at,
+			"effective_price": effective,
+			"history":         history,
+		})
+	}
+}
+
+// ScheduleFoodPrice records a new price for a food item, effective now or at a future effective_at
+func ScheduleFoodPrice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		foodID := c.Param("food_id")
+		var price models.FoodPrice
+
+		if err := c.BindJSON(&price); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(price)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		// History is append-only: backdating would change what past orders should have cost
+		now := time.Now()
+		if price.EffectiveAt.IsZero() {
+			price.EffectiveAt = now
+		} else if price.EffectiveAt.Before(now.Add(-time.Minute)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "effective_at cannot be in the past"})
+			return
+		}
+
+		// Prices of foods on versioned menus change through the menu draft
+		if err := checkDirectFoodEdit(ctx, foodID); err != nil {
+			directEditError(c, err, "Food item")
+			return
+		}
+
+		record, err := recordFoodPrice(ctx, foodID, *price.Price, price.EffectiveAt, c.GetString("uid"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Price could not be recorded"})
+			return
+		}
+
+		// Prices effective immediately are mirrored onto the food document
+		if !record.EffectiveAt.After(now) {
+			_, err = database.FoodCollection.UpdateOne(ctx, bson.M{"food_id": foodID}, bson.D{{Key: "$set", Value: bson.D{
+				{Key: "price", Value: record.Price},
+				{Key: "updated_at", Value: now},
+			}}})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Food item update failed"})
+				return
+			}
+		}
+
+		c.JSON(http.StatusCreated, record)
+	}
+}
+
+// CancelScheduledFoodPrice removes a price that has not taken effect yet
+func CancelScheduledFoodPrice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{
+			"food_id":      c.Param("food_id"),
+			"price_id":     c.Param("price_id"),
+			"effective_at": bson.M{"$gt": time.Now()},
+		}
+
+		result, err := database.FoodPriceCollection.DeleteOne(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel scheduled price"})
+			return
+		}
+
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No scheduled price found (prices already in effect cannot be removed)"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Scheduled price cancelled successfully"})
+	}
+}
+
+// FoodPriceAt resolves the price of a food item effective at the given time.
+// Foods created before price history existed fall back to their stored price.
+func FoodPriceAt(ctx context.Context, foodID string, at time.Time) (money.Money, error) {
+	var record models.FoodPrice
+	opts := options.FindOne().SetSort(bson.D{{Key: "effective_at", Value: -1}})
+	filter := bson.M{"food_id": foodID, "effective_at": bson.M{"$lte": at}}
+
+	err := database.FoodPriceCollection.FindOne(ctx, filter, opts).Decode(&record)
+	if err == nil && record.Price != nil {
+		return *record.Price, nil
+	}
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return money.Money{}, err
+	}
+
+	var food models.Food
+	if err := database.FoodCollection.FindOne(ctx, bson.M{"food_id": foodID}).Decode(&food); err != nil {
+		return money.Money{}, err
+	}
+	if food.Price == nil {
+		return money.Money{}, errors.New("food item has no price")
+	}
+	return *food.Price, nil
+}
+
+// recordFoodPrice appends a new version to a food item's price history.
+// Prices already in effect are marked applied: the caller copies them onto the food document.
+func recordFoodPrice(ctx context.Context, foodID string, price money.Money, effectiveAt time.Time, createdBy string) (models.FoodPrice, error) {
+	// Versions are unique per food; a concurrent writer taking the same number makes us retry with the next one.
+	// Inside a transaction the duplicate key has already aborted it, so the caller has to retry the transaction.
+	inTransaction := mongo.SessionFromContext(ctx) != nil
+	for attempt := 0; ; attempt++ {
+		var latest models.FoodPrice
+		opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+		err := database.FoodPriceCollection.FindOne(ctx, bson.M{"food_id": foodID}, opts).Decode(&latest)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return models.FoodPrice{}, err
+		}
+
+		now := time.Now()
+		record := models.FoodPrice{
+			ID:          primitive.NewObjectID(),
+			FoodID:      foodID,
+			Price:       &price,
+			EffectiveAt: effectiveAt,
+			Version:     latest.Version + 1,
+			CreatedBy:   createdBy,
+			CreatedAt:   now,
+		}
+		record.PriceID = record.ID.Hex()
+		if !effectiveAt.After(now) {
+			record.AppliedAt = &now
+		}
+
+		_, err = database.FoodPriceCollection.InsertOne(ctx, record)
+		if mongo.IsDuplicateKeyError(err) && !inTransaction && attempt < 5 {
+			continue
+		}
+		if err != nil {
+			return models.FoodPrice{}, err
+		}
+		return record, nil
+	}
+}
+
+// ApplyDueFoodPrices copies scheduled prices that have taken effect onto their food documents,
+// so filters and sorts on the stored price see them. It returns how many foods changed.
+func ApplyDueFoodPrices(ctx context.Context) (int, error) {
+	now := time.Now()
+	filter := bson.M{"applied_at": bson.M{"$exists": false}, "effective_at": bson.M{"$lte": now}}
+	result, err := database.FoodPriceCollection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	due := []models.FoodPrice{}
+	if err = result.All(ctx, &due); err != nil {
+		return 0, err
+	}
+
+	applied := map[string]bool{}
+	for _, record := range due {
+		if applied[record.FoodID] {
+			continue
+		}
+		// Several due records of a food resolve to the latest of them
+		price, err := FoodPriceAt(ctx, record.FoodID, now)
+		if err != nil {
+			return len(applied), err
+		}
+		_, err = database.FoodCollection.UpdateOne(ctx, bson.M{"food_id": record.FoodID}, bson.D{{Key: "$set", Value: bson.D{
+			{Key: "price", Value: price},
+			{Key: "updated_at", Value: now},
+		}}})
+		if err != nil {
+			return len(applied), err
+		}
+		_, err = database.FoodPriceCollection.UpdateMany(ctx,
+			bson.M{"food_id": record.FoodID, "applied_at": bson.M{"$exists": false}, "effective_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"applied_at": now}},
+		)
+		if err != nil {
+			return len(applied), err
+		}
+		applied[record.FoodID] = true
+	}
+
+	if len(applied) > 0 {
+		cache.PublicMenu.Invalidate()
+	}
+	return len(applied), nil
+}
+
+// RunPriceScheduler applies due scheduled prices every interval until ctx is done
+func RunPriceScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, interval)
+		if count, err := ApplyDueFoodPrices(runCtx); err != nil {
+			log.Printf("applying scheduled prices failed: %v", err)
+		} else if count > 0 {
+			log.Printf("applied scheduled prices to %d food items", count)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// currentPriceStages replaces each food's price with the one effective at the given time
+func currentPriceStages(at time.Time) mongo.Pipeline {
+	lookupStage := bson.D{{Key: "$lookup", Value: bson.D{
+		{Key: "from", Value: "foodPrice"},
+		{Key: "let", Value: bson.D{{Key: "foodID", Value: "$food_id"}}},
+		{Key: "pipeline", Value: mongo.Pipeline{
+			{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{{Key: "$and", Value: bson.A{
+				bson.D{{Key: "$eq", Value: bson.A{"$food_id", "$$foodID"}}},
+				bson.D{{Key: "$lte", Value: bson.A{"$effective_at", at}}},
+			}}}}}}},
+			{{Key: "$sort", Value: bson.D{{Key: "effective_at", Value: -1}}}},
+			{{Key: "$limit", Value: 1}},
+		}},
+		{Key: "as", Value: "effective_price"},
+	}}}
+	addFieldsStage := bson.D{{Key: "$addFields", Value: bson.D{
+		{Key: "price", Value: bson.D{{Key: "$ifNull", Value: bson.A{
+			bson.D{{Key: "$arrayElemAt", Value: bson.A{"$effective_price.price", 0}}},
+			"$price",
+		}}}},
+	}}}
+	projectStage := bson.D{{Key: "$project", Value: bson.D{{Key: "effective_price", Value: 0}}}}
+
+	return mongo.Pipeline{lookupStage, addFieldsStage, projectStage}
+}
@@ -0,0 +1,465 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Get all pricing rules, optionally filtered by ?scope= and ?target_id=
+func GetPricingRules() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{}
+		if scope := c.Query("scope"); scope != "" {
+			filter["scope"] = scope
+		}
+		if targetID := c.Query("target_id"); targetID != "" {
+			filter["target_ids"] = targetID
+		}
+
+		opts := options.Find().SetSort(bson.D{{Key: "priority", Value: -1}})
+		result, err := database.PricingRuleCollection.Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing pricing rules"})
+			return
+		}
+
+		allRules := []models.PricingRule{}
+		if err = result.All(ctx, &allRules); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing pricing rules"})
+			return
+		}
+
+		c.JSON(http.StatusOK, allRules)
+	}
+}
+
+// Get a single pricing rule by ID
+func GetPricingRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var rule models.PricingRule
+		err := database.PricingRuleCollection.FindOne(ctx, bson.M{"rule_id": c.Param("rule_id")}).Decode(&rule)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pricing rule not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, rule)
+	}
+}
+
+// Create a new pricing rule
+func CreatePricingRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var rule models.PricingRule
+		if err := c.BindJSON(&rule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := validatePricingRule(rule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		rule.CreatedAt = time.Now()
+		rule.UpdatedAt = time.Now()
+		rule.ID = primitive.NewObjectID()
+		rule.RuleID = rule.ID.Hex()
+
+		if _, err := database.PricingRuleCollection.InsertOne(ctx, rule); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Pricing rule was not created"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, rule)
+	}
+}
+
+// Struct to hold a partial pricing rule update; only the fields present in the body are changed
+type PricingRulePatch struct {
+	Name       *string      `json:"name"`
+	Scope      *string      `json:"scope"`
+	TargetIDs  []string     `json:"target_ids"`
+	Days       *[]int       `json:"days"`
+	StartTime  *string      `json:"start_time"`
+	EndTime    *string      `json:"end_time"`
+	ValidFrom  *time.Time   `json:"valid_from"`
+	ValidUntil *time.Time   `json:"valid_until"`
+	Adjustment *string      `json:"adjustment"`
+	Percent    *float64     `json:"percent"`
+	Amount     *money.Money `json:"amount"`
+	Priority   *int         `json:"priority"`
+	Active     *bool        `json:"active"`
+}
+
+// Update a pricing rule; the given fields are validated merged with the stored rule and only they are written
+func UpdatePricingRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		ruleID := c.Param("rule_id")
+		var rule models.PricingRule
+		if err := database.PricingRuleCollection.FindOne(ctx, bson.M{"rule_id": ruleID}).Decode(&rule); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pricing rule not found"})
+			return
+		}
+
+		var patch PricingRulePatch
+		if err := c.BindJSON(&patch); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var updateObj primitive.D
+		if patch.Name != nil {
+			rule.Name = patch.Name
+			updateObj = append(updateObj, bson.E{Key: "name", Value: rule.Name})
+		}
+		if patch.Scope != nil {
+			rule.Scope = *patch.Scope
+			updateObj = append(updateObj, bson.E{Key: "scope", Value: rule.Scope})
+		}
+		if patch.TargetIDs != nil {
+			rule.TargetIDs = patch.TargetIDs
+			updateObj = append(updateObj, bson.E{Key: "target_ids", Value: rule.TargetIDs})
+		}
+		if patch.Days != nil {
+			rule.Days = *patch.Days
+			updateObj = append(updateObj, bson.E{Key: "days", Value: rule.Days})
+		}
+		if patch.StartTime != nil {
+			rule.StartTime = *patch.StartTime
+			updateObj = append(updateObj, bson.E{Key: "start_time", Value: rule.StartTime})
+		}
+		if patch.EndTime != nil {
+			rule.EndTime = *patch.EndTime
+			updateObj = append(updateObj, bson.E{Key: "end_time", Value: rule.EndTime})
+		}
+		if patch.ValidFrom != nil {
+			rule.ValidFrom = patch.ValidFrom
+			updateObj = append(updateObj, bson.E{Key: "valid_from", Value: rule.ValidFrom})
+		}
+		if patch.ValidUntil != nil {
+			rule.ValidUntil = patch.ValidUntil
+			updateObj = append(updateObj, bson.E{Key: "valid_until", Value: rule.ValidUntil})
+		}
+		if patch.Adjustment != nil {
+			rule.Adjustment = *patch.Adjustment
+			updateObj = append(updateObj, bson.E{Key: "adjustment", Value: rule.Adjustment})
+		}
+		if patch.Percent != nil {
+			rule.Percent = *patch.Percent
+			updateObj = append(updateObj, bson.E{Key: "percent", Value: rule.Percent})
+		}
+		if patch.Amount != nil {
+			rule.Amount = patch.Amount
+			updateObj = append(updateObj, bson.E{Key: "amount", Value: rule.Amount})
+		}
+		if patch.Priority != nil {
+			rule.Priority = *patch.Priority
+			updateObj = append(updateObj, bson.E{Key: "priority", Value: rule.Priority})
+		}
+		if patch.Active != nil {
+			rule.Active = patch.Active
+			updateObj = append(updateObj, bson.E{Key: "active", Value: rule.Active})
+		}
+
+		// The rule has to stay consistent as a whole, e.g. a time window needs both ends
+		if err := validatePricingRule(rule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		rule.UpdatedAt = time.Now()
+		updateObj = append(updateObj, bson.E{Key: "updated_at", Value: rule.UpdatedAt})
+
+		_, err := database.PricingRuleCollection.UpdateOne(ctx, bson.M{"rule_id": ruleID}, bson.D{{Key: "$set", Value: updateObj}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Pricing rule update failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, rule)
+	}
+}
+
+// Delete a pricing rule
+func DeletePricingRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		result, err := database.PricingRuleCollection.DeleteOne(ctx, bson.M{"rule_id": c.Param("rule_id")})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pricing rule"})
+			return
+		}
+
+		if result.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pricing rule not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Pricing rule deleted successfully"})
+	}
+}
+
+// GetFoodEffectivePrice previews what a food costs at ?at=<RFC3339> (default now), pricing rules included
+func GetFoodEffectivePrice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		at := time.Now()
+		if v := c.Query("at"); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid at timestamp, expected RFC3339"})
+				return
+			}
+			at = parsed
+		}
+
+		var food models.Food
+		if err := database.FoodCollection.FindOne(ctx, bson.M{"food_id": c.Param("food_id")}).Decode(&food); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food item not found"})
+			return
+		}
+
+		base, err := FoodPriceAt(ctx, food.FoodID, at)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while resolving price"})
+			return
+		}
+
+		price, rule, err := ApplyPricingRules(ctx, food, base, at)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while applying pricing rules"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"food_id":    food.FoodID,
+			"at":         at,
+			"base_price": base,
+			"price":      price,
+			"rule":       rule,
+		})
+	}
+}
+
+// ApplyPricingRules adjusts a food's base price with the pricing rule in effect at the given time.
+// Rules don't stack: the highest priority match wins, ties go to the cheaper result.
+// The returned rule is nil when no rule applies.
+func ApplyPricingRules(ctx context.Context, food models.Food, base money.Money, at time.Time) (money.Money, *models.PricingRule, error) {
+	targets := bson.A{bson.M{"scope": "food", "target_ids": food.FoodID}}
+	if food.MenuID != nil {
+		targets = append(targets, bson.M{"scope": "menu", "target_ids": *food.MenuID})
+	}
+	if food.CategoryID != nil {
+		targets = append(targets, bson.M{"scope": "category", "target_ids": *food.CategoryID})
+	}
+	filter := bson.M{"$or": targets, "active": bson.M{"$ne": false}}
+
+	cursor, err := database.PricingRuleCollection.Find(ctx, filter)
+	if err != nil {
+		return base, nil, err
+	}
+	var rules []models.PricingRule
+	if err = cursor.All(ctx, &rules); err != nil {
+		return base, nil, err
+	}
+
+	price := base
+	var applied *models.PricingRule
+	for i := range rules {
+		rule := rules[i]
+		if !pricingRuleActiveAt(rule, at) {
+			continue
+		}
+		adjusted, err := adjustPrice(rule, base)
+		if err != nil {
+			return base, nil, err
+		}
+		if applied == nil || rule.Priority > applied.Priority ||
+			(rule.Priority == applied.Priority && adjusted.Amount < price.Amount) {
+			price = adjusted
+			applied = &rules[i]
+		}
+	}
+
+	return price, applied, nil
+}
+
+// pricingRuleActiveAt reports whether the rule's dates, days and daily window cover the moment.
+// Windows are evaluated in the server's local time zone (set TZ to the restaurant's zone).
+func pricingRuleActiveAt(rule models.PricingRule, at time.Time) bool {
+	if rule.ValidFrom != nil && at.Before(*rule.ValidFrom) {
+		return false
+	}
+	if rule.ValidUntil != nil && at.After(*rule.ValidUntil) {
+		return false
+	}
+
+	local := at.In(time.Local)
+	day := local.Weekday()
+	if rule.StartTime != "" && rule.EndTime != "" {
+		start, _ := time.Parse("15:04", rule.StartTime)
+		end, _ := time.Parse("15:04", rule.EndTime)
+		minute := local.Hour()*60 + local.Minute()
+		from := start.Hour()*60 + start.Minute()
+		to := end.Hour()*60 + end.Minute()
+
+		if from <= to {
+			if minute < from || minute >= to {
+				return false
+			}
+		} else {
+			// The window wraps past midnight; its early-morning part belongs to the previous day
+			if minute >= to && minute < from {
+				return false
+			}
+			if minute < to {
+				day = (day + 6) % 7
+			}
+		}
+	}
+
+	if len(rule.Days) == 0 {
+		return true
+	}
+	for _, d := range rule.Days {
+		if time.Weekday(d) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// nextPricingRuleChange returns the first moment after now at which the rule may start or stop applying,
+// or the zero time if it never changes again. Rules limited to some days may change at every local midnight.
+func nextPricingRuleChange(rule models.PricingRule, now time.Time) time.Time {
+	var next time.Time
+	consider := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	if rule.ValidUntil != nil {
+		if !now.Before(*rule.ValidUntil) {
+			return time.Time{}
+		}
+		// The rule still applies at valid_until itself
+		consider(rule.ValidUntil.Add(time.Nanosecond))
+	}
+	if rule.ValidFrom != nil {
+		consider(*rule.ValidFrom)
+	}
+
+	local := now.In(time.Local)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+	for _, clock := range []string{rule.StartTime, rule.EndTime} {
+		t, err := time.Parse("15:04", clock)
+		if err != nil {
+			continue
+		}
+		today := time.Date(local.Year(), local.Month(), local.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		consider(today)
+		consider(today.AddDate(0, 0, 1))
+	}
+	if len(rule.Days) > 0 {
+		consider(midnight.AddDate(0, 0, 1))
+	}
+	return next
+}
+
+// adjustPrice applies a rule's adjustment to a price, never going below zero
+func adjustPrice(rule models.PricingRule, base money.Money) (money.Money, error) {
+	var adjusted money.Money
+	switch rule.Adjustment {
+	case "percent":
+		// Percentages are kept to two decimals so the ratio stays exact
+		var err error
+		if adjusted, err = base.MulRatio(int64(math.Round((100+rule.Percent)*100)), 10000, money.Rounding()); err != nil {
+			return base, err
+		}
+	case "fixed":
+		if rule.Amount == nil {
+			return base, nil
+		}
+		var err error
+		if adjusted, err = base.Add(*rule.Amount); err != nil {
+			return base, err
+		}
+	default:
+		return base, nil
+	}
+
+	if adjusted.Amount < 0 {
+		adjusted = money.Zero(base.Currency)
+	}
+	return adjusted, nil
+}
+
+// validatePricingRule checks the rule beyond what struct tags can express
+func validatePricingRule(rule models.PricingRule) error {
+	if validationErr := helpers.Validate.Struct(rule); validationErr != nil {
+		return validationErr
+	}
+	if (rule.StartTime == "") != (rule.EndTime == "") {
+		return errors.New("start_time and end_time must be set together")
+	}
+	if rule.StartTime != "" && rule.StartTime == rule.EndTime {
+		return errors.New("start_time and end_time must differ")
+	}
+	if rule.ValidFrom != nil && rule.ValidUntil != nil && !rule.ValidUntil.After(*rule.ValidFrom) {
+		return errors.New("valid_until must be after valid_from")
+	}
+	switch rule.Adjustment {
+	case "percent":
+		if rule.Percent == 0 {
+			return errors.New("percent rules need a non-zero percent")
+		}
+	case "fixed":
+		if rule.Amount == nil || rule.Amount.IsZero() {
+			return errors.New("fixed rules need a non-zero amount")
+		}
+		if rule.Amount.Currency != money.DefaultCurrency() {
+			return fmt.Errorf("amount must be in %s", money.DefaultCurrency())
+		}
+	}
+	return nil
+}
+
+// ruleID returns the ID of an applied rule, or nil when none applied
+func ruleID(rule *models.PricingRule) *string {
+	if rule == nil {
+		return nil
+	}
+	id := rule.RuleID
+	return &id
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"testing"
+	"time"
+)
+
+func TestPricingRuleActiveAt(t *testing.T) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.UTC
+
+	// 2026-10-16 is a Friday (5), 2026-10-17 a Saturday (6)
+	at := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	from, until := at("2026-10-01 00:00"), at("2026-10-31 00:00")
+
+	tests := []struct {
+		name string
+		rule models.PricingRule
+		at   string
+		want bool
+	}{
+		{"always", models.PricingRule{}, "2026-10-16 12:00", true},
+		{"before valid_from", models.PricingRule{ValidFrom: &from}, "2026-09-30 23:59", false},
+		{"from valid_from", models.PricingRule{ValidFrom: &from}, "2026-10-01 00:00", true},
+		{"after valid_until", models.PricingRule{ValidUntil: &until}, "2026-10-31 00:01", false},
+		{"inside window", models.PricingRule{StartTime: "16:00", EndTime: "18:00"}, "2026-10-16 16:00", true},
+		{"window end is exclusive", models.PricingRule{StartTime: "16:00", EndTime: "18:00"}, "2026-10-16 18:00", false},
+		{"before window", models.PricingRule{StartTime: "16:00", EndTime: "18:00"}, "2026-10-16 15:59", false},
+		{"matching day", models.PricingRule{Days: []int{5}}, "2026-10-16 09:00", true},
+		{"other day", models.PricingRule{Days: []int{6}}, "2026-10-16 09:00", false},
+		{"wrapping window late part", models.PricingRule{StartTime: "22:00", EndTime: "02:00", Days: []int{5}}, "2026-10-16 23:00", true},
+		{"wrapping window early part belongs to previous day", models.PricingRule{StartTime: "22:00", EndTime: "02:00", Days: []int{5}}, "2026-10-17 01:30", true},
+		{"wrapping window early part of a day not listed", models.PricingRule{StartTime: "22:00", EndTime: "02:00", Days: []int{5}}, "2026-10-16 01:30", false},
+		{"outside wrapping window", models.PricingRule{StartTime: "22:00", EndTime: "02:00"}, "2026-10-16 12:00", false},
+	}
+	for _, tt := range tests {
+		if got := pricingRuleActiveAt(tt.rule, at(tt.at)); got != tt.want {
+			t.Errorf("%s: active at %s = %v, want %v", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestAdjustPrice(t *testing.T) {
+	discount := money.New(-1500, "USD")
+	tests := []struct {
+		name string
+		rule models.PricingRule
+		want int64
+	}{
+		{"percent off", models.PricingRule{Adjustment: "percent", Percent: -20}, 800},
+		{"percent up, rounded", models.PricingRule{Adjustment: "percent", Percent: 12.5}, 1125},
+		{"fixed never below zero", models.PricingRule{Adjustment: "fixed", Amount: &discount}, 0},
+		{"unknown adjustment", models.PricingRule{Adjustment: "other"}, 1000},
+	}
+	for _, tt := range tests {
+		got, err := adjustPrice(tt.rule, money.New(1000, "USD"))
+		if err != nil || got.Amount != tt.want {
+			t.Errorf("%s: %v (%v), want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestNextPricingRuleChange(t *testing.T) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.UTC
+
+	at := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	from, until := at("2026-10-20 00:00"), at("2026-10-31 00:00")
+	past := at("2026-10-01 00:00")
+
+	tests := []struct {
+		name string
+		rule models.PricingRule
+		want time.Time
+	}{
+		{"never changes", models.PricingRule{}, time.Time{}},
+		{"starts later", models.PricingRule{ValidFrom: &from}, from},
+		{"ends later", models.PricingRule{ValidUntil: &until}, until.Add(time.Nanosecond)},
+		{"already ended", models.PricingRule{ValidUntil: &past, StartTime: "16:00", EndTime: "18:00"}, time.Time{}},
+		{"window start today", models.PricingRule{StartTime: "16:00", EndTime: "18:00"}, at("2026-10-16 16:00")},
+		{"window start tomorrow", models.PricingRule{StartTime: "09:00", EndTime: "11:00"}, at("2026-10-17 09:00")},
+		{"days change at midnight", models.PricingRule{Days: []int{5}}, at("2026-10-17 00:00")},
+	}
+	now := at("2026-10-16 12:00")
+	for _, tt := range tests {
+		if got := nextPricingRuleChange(tt.rule, now); !got.Equal(tt.want) {
+			t.Errorf("%s: next change = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
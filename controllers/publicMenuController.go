@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-restaurant-management/cache"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/models"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Public view of a menu, without internal fields such as draft versions
+type PublicMenu struct {
+	MenuID        string          `json:"menu_id"`
+	Name          string          `json:"name"`
+	Category      string          `json:"category"`
+	StartDate     *time.Time      `json:"start_date,omitempty"`
+	EndDate       *time.Time      `json:"end_date,omitempty"`
+	Categories    []*CategoryNode `json:"categories,omitempty"`
+	Uncategorized []bson.M        `json:"uncategorized,omitempty"`
+}
+
+// GetPublicMenus lists the menus currently on offer (no authentication required)
+func GetPublicMenus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := requestLocale(c)
+
+		servePublic(c, "menus|"+locale, func(ctx context.Context) (interface{}, error) {
+			result, err := database.MenuCollection.Find(ctx, liveMenuFilter(time.Now()))
+			if err != nil {
+				return nil, err
+			}
+			var menus []models.Menu
+			if err = result.All(ctx, &menus); err != nil {
+				return nil, err
+			}
+
+			publicMenus := []PublicMenu{}
+			for _, menu := range menus {
+				localizeMenu(&menu, locale)
+				publicMenus = append(publicMenus, publicMenuOf(menu))
+			}
+			return publicMenus, nil
+		})
+	}
+}
+
+// GetPublicMenu returns a live menu with its categories and available foods (no authentication required)
+func GetPublicMenu() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		menuID := c.Param("menu_id")
+		locale := requestLocale(c)
+
+		servePublic(c, "menu|"+menuID+"|"+locale, func(ctx context.Context) (interface{}, error) {
+			filter := liveMenuFilter(time.Now())
+			filter["menu_id"] = menuID
+
+			var menu models.Menu
+			if err := database.MenuCollection.FindOne(ctx, filter).Decode(&menu); err != nil {
+				return nil, err
+			}
+			localizeMenu(&menu, locale)
+
+			tree, uncategorized, err := MenuTree(ctx, menuID, locale, bson.M{"available": bson.M{"$ne": false}})
+			if err != nil {
+				return nil, err
+			}
+			foods := append([]bson.M{}, uncategorized...)
+			for _, node := range tree {
+				foods = append(foods, nodeFoods(node)...)
+			}
+			// Guests see what an order placed now would be charged
+			now := time.Now()
+			for _, food := range foods {
+				if err := pricePublicFood(ctx, food, now); err != nil {
+					return nil, err
+				}
+				publicFood(food)
+			}
+
+			publicMenu := publicMenuOf(menu)
+			publicMenu.Categories = tree
+			publicMenu.Uncategorized = uncategorized
+			return publicMenu, nil
+		})
+	}
+}
+
+// servePublic answers from the public menu cache, building and caching the response on a miss,
+// and honours If-None-Match / If-Modified-Since with 304 Not Modified
+func servePublic(c *gin.Context, key string, build func(ctx context.Context) (interface{}, error)) {
+	entry, ok := cache.PublicMenu.Get(key)
+	if !ok {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		data, err := build(ctx)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Menu not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while loading the menu"})
+			return
+		}
+
+		body, err := json.Marshal(data)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while rendering the menu"})
+			return
+		}
+
+		// Scheduled prices, pricing rule windows and menu dates change the response without any write
+		// that would invalidate the cache, so the entry must not outlive the next such change
+		until, err := nextPublicMenuChange(ctx, time.Now())
+		if err != nil {
+			log.Printf("public menu: finding the next price change failed: %v", err)
+		}
+		entry = cache.PublicMenu.SetUntil(key, body, until)
+	}
+
+	c.Header("ETag", entry.ETag)
+	c.Header("Last-Modified", entry.LastModified.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(entry.MaxAge().Seconds())))
+
+	if notModified(c, entry) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", entry.Body)
+}
+
+// notModified evaluates the request's conditional headers; If-None-Match takes precedence as in RFC 9110
+func notModified(c *gin.Context, entry cache.Entry) bool {
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == entry.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := c.GetHeader("If-Modified-Since"); since != "" {
+		t, err := http.ParseTime(since)
+		return err == nil && !entry.LastModified.After(t)
+	}
+	return false
+}
+
+// liveMenuFilter matches menus whose availability window covers the given time (open-ended dates count as open)
+func liveMenuFilter(at time.Time) bson.M {
+	return bson.M{"$and": bson.A{
+		bson.M{"$or": bson.A{bson.M{"start_date": nil}, bson.M{"start_date": bson.M{"$lte": at}}}},
+		bson.M{"$or": bson.A{bson.M{"end_date": nil}, bson.M{"end_date": bson.M{"$gte": at}}}},
+	}}
+}
+
+// publicMenuOf copies the fields of a menu guests may see
+func publicMenuOf(menu models.Menu) PublicMenu {
+	return PublicMenu{
+		MenuID:    menu.MenuID,
+		Name:      menu.Name,
+		Category:  menu.Category,
+		StartDate: menu.StartDate,
+		EndDate:   menu.EndDate,
+	}
+}
+
+// nodeFoods returns the foods of a category node and of all nodes below it
+func nodeFoods(node *CategoryNode) []bson.M {
+	foods := append([]bson.M{}, node.Foods...)
+	for _, child := range node.Children {
+		foods = append(foods, nodeFoods(child)...)
+	}
+	return foods
+}
+
+// pricePublicFood replaces a food's price with the one orders placed at the given time are charged:
+// the price effective then, adjusted by the pricing rule running then
+func pricePublicFood(ctx context.Context, doc bson.M, at time.Time) error {
+	var food models.Food
+	if err := decodeDocument(doc, &food); err != nil {
+		return err
+	}
+	base, err := FoodPriceAt(ctx, food.FoodID, at)
+	if err != nil {
+		return err
+	}
+	price, _, err := ApplyPricingRules(ctx, food, base, at)
+	if err != nil {
+		return err
+	}
+	doc["price"] = price
+	return nil
+}
+
+// nextPublicMenuChange returns the next moment after now at which a public response may change on its own:
+// a scheduled price taking effect, a pricing rule starting or stopping, or a menu opening or closing.
+// The zero time means no such change is known.
+func nextPublicMenuChange(ctx context.Context, now time.Time) (time.Time, error) {
+	var next time.Time
+	consider := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	var price models.FoodPrice
+	opts := options.FindOne().SetSort(bson.D{{Key: "effective_at", Value: 1}})
+	err := database.FoodPriceCollection.FindOne(ctx, bson.M{"effective_at": bson.M{"$gt": now}}, opts).Decode(&price)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return next, err
+	}
+	consider(price.EffectiveAt)
+
+	cursor, err := database.PricingRuleCollection.Find(ctx, bson.M{"active": bson.M{"$ne": false}})
+	if err != nil {
+		return next, err
+	}
+	var rules []models.PricingRule
+	if err = cursor.All(ctx, &rules); err != nil {
+		return next, err
+	}
+	for _, rule := range rules {
+		consider(nextPricingRuleChange(rule, now))
+	}
+
+	for _, field := range []string{"start_date", "end_date"} {
+		var menu models.Menu
+		opts := options.FindOne().SetSort(bson.D{{Key: field, Value: 1}})
+		err := database.MenuCollection.FindOne(ctx, bson.M{field: bson.M{"$gt": now}}, opts).Decode(&menu)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return next, err
+		}
+		if menu.StartDate != nil && field == "start_date" {
+			consider(*menu.StartDate)
+		}
+		if menu.EndDate != nil && field == "end_date" {
+			// Menus are still live at their end date itself
+			consider(menu.EndDate.Add(time.Nanosecond))
+		}
+	}
+	return next, nil
+}
+
+// publicFood removes fields that are only meaningful to staff
+func publicFood(food bson.M) {
+	for _, field := range []string{"_id", "translations", "created_at", "updated_at", "available"} {
+		delete(food, field)
+	}
+}
@@ -0,0 +1,583 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/repository"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Reservation statuses
+const (
+	ReservationBooked    = "booked"
+	ReservationSeated    = "seated"
+	ReservationCancelled = "cancelled"
+	ReservationNoShow    = "no_show"
+)
+
+// DefaultReservationDuration is used when a booking doesn't say how long the party stays
+const DefaultReservationDuration = 90
+
+// ErrNoTableAvailable is returned when no table can host a party at the requested time
+var ErrNoTableAvailable = errors.New("no table available for this party size and time")
+
+// ErrTableBooked is returned when the requested table already has an overlapping booking
+var ErrTableBooked = errors.New("table is already booked at this time")
+
+// ErrReservationChanged is returned when a reservation left the booked status while it was being changed
+var ErrReservationChanged = errors.New("reservation changed concurrently")
+
+// Get reservations, optionally filtered by ?date=YYYY-MM-DD and ?status=
+func GetReservations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{}
+		if status := c.Query("status"); status != "" {
+			filter["status"] = status
+		}
+		if date := c.Query("date"); date != "" {
+			day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, expected YYYY-MM-DD"})
+				return
+			}
+			filter["reserved_at"] = bson.M{"$gte": day, "$lt": day.AddDate(0, 0, 1)}
+		}
+
+		opts := options.Find().SetSort(bson.D{{Key: "reserved_at", Value: 1}})
+		result, err := database.ReservationCollection.Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing reservations"})
+			return
+		}
+
+		reservations := []models.Reservation{}
+		if err = result.All(ctx, &reservations); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing reservations"})
+			return
+		}
+
+		c.JSON(http.StatusOK, reservations)
+	}
+}
+
+// Get a single reservation by ID
+func GetReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var reservation models.Reservation
+		err := database.ReservationCollection.FindOne(ctx, bson.M{"reservation_id": c.Param("reservation_id")}).Decode(&reservation)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, reservation)
+	}
+}
+
+// GetReservationAvailability lists the tables that can host ?party_size= at ?time= for ?duration= minutes
+func GetReservationAvailability() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		start, err := time.Parse(time.RFC3339, c.Query("time"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time, expected RFC3339"})
+			return
+		}
+		partySize, err := strconv.Atoi(c.Query("party_size"))
+		if err != nil || partySize < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "party_size must be a positive number"})
+			return
+		}
+		duration := DefaultReservationDuration
+		if v := c.Query("duration"); v != "" {
+			duration, err = strconv.Atoi(v)
+			if err != nil || duration < 1 || duration > 480 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "duration must be between 1 and 480 minutes"})
+				return
+			}
+		}
+		end := start.Add(time.Duration(duration) * time.Minute)
+
+		tables, err := availableTables(ctx, partySize, start, end, "")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while checking availability"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"time":       start,
+			"ends_at":    end,
+			"party_size": partySize,
+			"available":  len(tables) > 0,
+			"tables":     tables,
+		})
+	}
+}
+
+// CreateReservation books a table, assigning the best fitting free table unless table_id is given
+func CreateReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var reservation models.Reservation
+		if err := c.BindJSON(&reservation); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(reservation)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		reservation.OverriddenBy = nil
+		if !authorizeCapacityOverride(ctx, c, &reservation) {
+			return
+		}
+
+		reservation.ID = primitive.NewObjectID()
+		reservation.ReservationID = reservation.ID.Hex()
+		reservation.Status = ReservationBooked
+		reservation.CreatedAt = time.Now()
+		reservation.UpdatedAt = time.Now()
+
+		booked, err := bookReservation(ctx, reservation, assignReservationTable, func(sc mongo.SessionContext, booking models.Reservation) error {
+			_, err := database.ReservationCollection.InsertOne(sc, booking)
+			return err
+		})
+		if err != nil {
+			reservationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, booked)
+	}
+}
+
+// UpdateReservation changes a booking; time, party size, duration and table are re-checked for conflicts
+func UpdateReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		reservationID := c.Param("reservation_id")
+		var reservation models.Reservation
+		if err := database.ReservationCollection.FindOne(ctx, bson.M{"reservation_id": reservationID}).Decode(&reservation); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+			return
+		}
+		if reservation.Status != ReservationBooked {
+			c.JSON(http.StatusConflict, gin.H{"error": "Only booked reservations can be changed"})
+			return
+		}
+
+		var input models.Reservation
+		if err := c.BindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		reassign := false
+		if input.GuestName != nil {
+			reservation.GuestName = input.GuestName
+		}
+		if input.Phone != nil {
+			reservation.Phone = input.Phone
+		}
+		if input.Notes != nil {
+			reservation.Notes = input.Notes
+		}
+		if input.PartySize != nil {
+			reservation.PartySize = input.PartySize
+			reassign = true
+		}
+		if input.ReservedAt != nil {
+			reservation.ReservedAt = input.ReservedAt
+			reassign = true
+		}
+		if input.DurationMinutes != 0 {
+			reservation.DurationMinutes = input.DurationMinutes
+			reassign = true
+		}
+		if input.TableID != nil {
+			reservation.TableID = input.TableID
+			reassign = true
+		}
+		// An override only covers the party and table it was given for
+		if input.CapacityOverride || input.PartySize != nil || input.TableID != nil {
+			reservation.CapacityOverride = input.CapacityOverride
+			reservation.OverriddenBy = nil
+			reassign = true
+		}
+
+		validationErr := helpers.Validate.Struct(reservation)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if !authorizeCapacityOverride(ctx, c, &reservation) {
+			return
+		}
+
+		assign := func(ctx context.Context, booking *models.Reservation) error {
+			if !reassign {
+				return nil
+			}
+			err := assignReservationTable(ctx, booking)
+			// Keep the current table if it still fits, otherwise pick another one
+			if err != nil && input.TableID == nil && booking.TableID != nil {
+				booking.TableID = nil
+				err = assignReservationTable(ctx, booking)
+			}
+			return err
+		}
+
+		reservation.UpdatedAt = time.Now()
+		updated, err := bookReservation(ctx, reservation, assign, func(sc mongo.SessionContext, booking models.Reservation) error {
+			filter := bson.M{"reservation_id": reservationID, "status": ReservationBooked}
+			result, err := database.ReservationCollection.ReplaceOne(sc, filter, booking)
+			if err != nil {
+				return err
+			}
+			if result.MatchedCount == 0 {
+				return ErrReservationChanged
+			}
+			return nil
+		})
+		if err != nil {
+			reservationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// CancelReservation releases a booked table
+func CancelReservation() gin.HandlerFunc {
+	return closeReservation(ReservationCancelled)
+}
+
+// MarkReservationNoShow releases the table of a party that did not turn up
+func MarkReservationNoShow() gin.HandlerFunc {
+	return closeReservation(ReservationNoShow)
+}
+
+// SeatReservation seats the party at its table and opens the table's order
+func SeatReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		reservationID := c.Param("reservation_id")
+		var reservation models.Reservation
+		if err := database.ReservationCollection.FindOne(ctx, bson.M{"reservation_id": reservationID}).Decode(&reservation); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+			return
+		}
+		if reservation.Status != ReservationBooked {
+			c.JSON(http.StatusConflict, gin.H{"error": "Only booked reservations can be seated"})
+			return
+		}
+		if reservation.TableID == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Reservation has no table assigned"})
+			return
+		}
+
+		// Seating, the order and the reservation change together or not at all, and only while the
+		// reservation is still booked, so a concurrent cancel or second seat can't leave a stray order
+		now := time.Now()
+		var order models.Order
+		var table models.Table
+		_, err := repository.Transaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+			// The table may have been resized since the booking; only an override recorded on it still applies
+			var current models.Table
+			if err := database.TableCollection.FindOne(sc, bson.M{"table_id": *reservation.TableID}).Decode(&current); err != nil {
+				return nil, err
+			}
+			override := reservation.CapacityOverride && reservation.OverriddenBy != nil
+			if err := checkTableCapacity(current, *reservation.PartySize, override); err != nil {
+				return nil, err
+			}
+
+			seated, changed, err := transitionTable(sc, *reservation.TableID, TableSeated)
+			if err != nil {
+				return nil, err
+			}
+			if changed {
+				table = seated
+			}
+
+			// The order is opened directly so the table stays seated until the first items are ordered
+			order = models.Order{
+				ID:        primitive.NewObjectID(),
+				Type:      OrderDineIn,
+				TableID:   reservation.TableID,
+				OrderDate: now,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			order.OrderID = order.ID.Hex()
+			initOrderStatus(&order, c.GetString("uid"))
+			if err := repository.Orders().Insert(sc, &order); err != nil {
+				return nil, err
+			}
+
+			update := bson.M{"$set": bson.M{
+				"status":     ReservationSeated,
+				"order_id":   order.OrderID,
+				"seated_at":  now,
+				"updated_at": now,
+			}}
+			result, err := database.ReservationCollection.UpdateOne(sc, bson.M{"reservation_id": reservationID, "status": ReservationBooked}, update)
+			if err != nil {
+				return nil, err
+			}
+			if result.MatchedCount == 0 {
+				return nil, ErrReservationChanged
+			}
+			return nil, nil
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, mongo.ErrNoDocuments):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+			case errors.Is(err, ErrInvalidTableTransition):
+				c.JSON(http.StatusConflict, gin.H{"error": "Table is not ready for seating: " + err.Error()})
+			case errors.Is(err, ErrReservationChanged):
+				c.JSON(http.StatusConflict, gin.H{"error": "Only booked reservations can be seated"})
+			case errors.Is(err, ErrOverCapacity):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error() + " (a manager can set capacity_override on the reservation)"})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Reservation could not be seated"})
+			}
+			return
+		}
+
+		if table.TableID != "" {
+			publishTableEvent(events.TableStatusChanged, table)
+		}
+		publishOrderEvent(events.OrderCreated, order)
+		reservation.Status = ReservationSeated
+		reservation.OrderID = &order.OrderID
+		reservation.SeatedAt = &now
+		reservation.UpdatedAt = now
+
+		c.JSON(http.StatusOK, gin.H{"reservation": reservation, "order": order})
+	}
+}
+
+// closeReservation builds the handlers that end a booking without seating it
+func closeReservation(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{"reservation_id": c.Param("reservation_id"), "status": ReservationBooked}
+		update := bson.M{"$set": bson.M{"status": status, "updated_at": time.Now()}}
+
+		result, err := database.ReservationCollection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Reservation update failed"})
+			return
+		}
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No booked reservation found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Reservation marked " + status})
+	}
+}
+
+// bookReservation assigns the reservation's table and stores it with write in one transaction. The assigned
+// table's document is written before the booking, which makes it a lock: two bookings of the same table
+// conflict and the driver retries the later one, whose overlap check then sees the earlier booking.
+// assign and write run on a fresh copy of the reservation on every attempt; the stored copy is returned.
+func bookReservation(ctx context.Context, reservation models.Reservation, assign func(ctx context.Context, booking *models.Reservation) error, write func(sc mongo.SessionContext, booking models.Reservation) error) (models.Reservation, error) {
+	result, err := repository.Transaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		booking := reservation
+		if err := assign(sc, &booking); err != nil {
+			return nil, err
+		}
+		if booking.TableID != nil {
+			lock := bson.M{"$inc": bson.M{"booking_lock": 1}}
+			if _, err := database.TableCollection.UpdateOne(sc, bson.M{"table_id": *booking.TableID}, lock); err != nil {
+				return nil, err
+			}
+		}
+		if err := write(sc, booking); err != nil {
+			return nil, err
+		}
+		return booking, nil
+	})
+	if err != nil {
+		return reservation, err
+	}
+	return result.(models.Reservation), nil
+}
+
+// assignReservationTable fills in the booking's end time and checks its table, or picks the
+// smallest free table that fits the party when none is set
+func assignReservationTable(ctx context.Context, reservation *models.Reservation) error {
+	if reservation.DurationMinutes == 0 {
+		reservation.DurationMinutes = DefaultReservationDuration
+	}
+	reservation.EndsAt = reservation.ReservedAt.Add(time.Duration(reservation.DurationMinutes) * time.Minute)
+
+	if reservation.TableID != nil {
+		return checkReservationTable(ctx, *reservation)
+	}
+
+	tables, err := availableTables(ctx, *reservation.PartySize, *reservation.ReservedAt, reservation.EndsAt, reservation.ReservationID)
+	if err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return ErrNoTableAvailable
+	}
+	reservation.TableID = &tables[0].TableID
+	return nil
+}
+
+// checkReservationTable verifies that the booking's table exists, fits the party (unless a manager
+// overrode the capacity check) and is free for the whole window
+func checkReservationTable(ctx context.Context, reservation models.Reservation) error {
+	var table models.Table
+	if err := database.TableCollection.FindOne(ctx, bson.M{"table_id": *reservation.TableID}).Decode(&table); err != nil {
+		return err
+	}
+	if table.Status == TableOutOfService || table.MergedInto != nil || len(table.CombinedFrom) > 0 {
+		return ErrNoTableAvailable
+	}
+	if err := checkTableCapacity(table, *reservation.PartySize, reservation.CapacityOverride); err != nil {
+		return err
+	}
+
+	busy, err := bookedTables(ctx, *reservation.ReservedAt, reservation.EndsAt, reservation.ReservationID)
+	if err != nil {
+		return err
+	}
+	if busy[table.TableID] {
+		return ErrTableBooked
+	}
+	return nil
+}
+
+// availableTables lists the tables whose capacity fits the party and have no overlapping booking,
+// smallest fitting table first so large tables stay free for large parties
+func availableTables(ctx context.Context, partySize int, start, end time.Time, excludeReservationID string) ([]models.Table, error) {
+	filter := bson.M{
+		"status": bson.M{"$ne": TableOutOfService},
+		// Merged and temporary combined tables can't be booked ahead
+		"merged_into":   bson.M{"$exists": false},
+		"combined_from": bson.M{"$exists": false},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "number_of_guests", Value: 1}, {Key: "table_number", Value: 1}})
+	cursor, err := database.TableCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var tables []models.Table
+	if err = cursor.All(ctx, &tables); err != nil {
+		return nil, err
+	}
+
+	busy, err := bookedTables(ctx, start, end, excludeReservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	free := []models.Table{}
+	for _, table := range tables {
+		if tableFits(table, partySize) && !busy[table.TableID] {
+			free = append(free, table)
+		}
+	}
+	sort.SliceStable(free, func(i, j int) bool {
+		_, maxI := tableCapacity(free[i])
+		_, maxJ := tableCapacity(free[j])
+		return maxI < maxJ
+	})
+	return free, nil
+}
+
+// bookedTables returns the tables with an active booking overlapping [start, end)
+func bookedTables(ctx context.Context, start, end time.Time, excludeReservationID string) (map[string]bool, error) {
+	filter := bson.M{
+		"status":      bson.M{"$in": bson.A{ReservationBooked, ReservationSeated}},
+		"reserved_at": bson.M{"$lt": end},
+		"ends_at":     bson.M{"$gt": start},
+	}
+	if excludeReservationID != "" {
+		filter["reservation_id"] = bson.M{"$ne": excludeReservationID}
+	}
+
+	cursor, err := database.ReservationCollection.Find(ctx, filter, options.Find().SetProjection(bson.M{"table_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var overlapping []models.Reservation
+	if err = cursor.All(ctx, &overlapping); err != nil {
+		return nil, err
+	}
+
+	busy := map[string]bool{}
+	for _, reservation := range overlapping {
+		if reservation.TableID != nil {
+			busy[*reservation.TableID] = true
+		}
+	}
+	return busy, nil
+}
+
+// authorizeCapacityOverride checks that only managers override capacity checks and records who did.
+// It writes the error response and returns false when the caller may not.
+func authorizeCapacityOverride(ctx context.Context, c *gin.Context, reservation *models.Reservation) bool {
+	if !reservation.CapacityOverride || reservation.OverriddenBy != nil {
+		return true
+	}
+	uid := c.GetString("uid")
+	if !canOverrideCapacity(ctx, uid) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only managers can override table capacity"})
+		return false
+	}
+	reservation.OverriddenBy = &uid
+	return true
+}
+
+// reservationError maps table assignment errors to responses
+func reservationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+	case errors.Is(err, ErrNoTableAvailable), errors.Is(err, ErrTableBooked), errors.Is(err, ErrOverCapacity), errors.Is(err, ErrReservationChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while assigning a table"})
+	}
+}
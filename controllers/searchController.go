@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/money"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Maximum number of documents pulled from each query stage before ranking
+const searchCandidateLimit = 200
+
+// Number of leading runes of a term a typo-tolerant candidate must match within one edit
+const searchTypoPrefixLen = 4
+
+// Search runs a ranked full-text search across foods and menus
+func Search() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		query := strings.TrimSpace(c.Query("q"))
+		terms := helpers.SearchTerms(query)
+		if len(terms) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+			return
+		}
+
+		limit, err := strconv.Atoi(c.Query("limit"))
+		if err != nil || limit < 1 || limit > 100 {
+			limit = 20
+		}
+
+		// Build food filters
+		foodFilter := bson.M{}
+		if menuID := c.Query("menu_id"); menuID != "" {
+			foodFilter["menu_id"] = menuID
+		}
+
+		// Price bounds are given in major units and compared in minor units
+		priceFilter := bson.M{}
+		if v := c.Query("min_price"); v != "" {
+			minPrice, err := money.Parse(v, money.DefaultCurrency(), money.Rounding())
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_price"})
+				return
+			}
+			priceFilter["$gte"] = minPrice.Amount
+		}
+		if v := c.Query("max_price"); v != "" {
+			maxPrice, err := money.Parse(v, money.DefaultCurrency(), money.Rounding())
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid max_price"})
+				return
+			}
+			priceFilter["$lte"] = maxPrice.Amount
+		}
+		if len(priceFilter) > 0 {
+			foodFilter["price.amount"] = priceFilter
+		}
+
+		// Narrowing by food-specific filters leaves menus out of the results
+		narrowed := len(foodFilter) > 0
+
+		// Unavailable foods, including those publishing took off a menu, are only found when asked for
+		foodFilter["available"] = bson.M{"$ne": false}
+		if v := c.Query("available"); v != "" {
+			available, err := strconv.ParseBool(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid available flag"})
+				return
+			}
+			if !available {
+				foodFilter["available"] = false
+			}
+			narrowed = true
+		}
+
+		foods, err := rankedSearch(ctx, database.FoodCollection, "food_id", query, terms, foodFilter, []string{"name", "description"})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while searching food items"})
+			return
+		}
+
+		// Menu results are only relevant when not narrowing down to food-specific filters
+		menus := []bson.M{}
+		if !narrowed {
+			menus, err = rankedSearch(ctx, database.MenuCollection, "menu_id", query, terms, bson.M{}, []string{"name", "category"})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while searching menus"})
+				return
+			}
+		}
+
+		if len(foods) > limit {
+			foods = foods[:limit]
+		}
+		if len(menus) > limit {
+			menus = menus[:limit]
+		}
+
+		c.JSON(http.StatusOK, gin.H{"query": query, "foods": foods, "menus": menus})
+	}
+}
+
+// rankedSearch combines the collection's text index with prefix and typo-tolerant matching.
+// The first entry of fields is treated as the primary (name) field and weighs the most.
+func rankedSearch(ctx context.Context, collection *mongo.Collection, idKey, query string, terms []string, filter bson.M, fields []string) ([]bson.M, error) {
+	results := map[string]bson.M{}
+	scores := map[string]float64{}
+
+	// Stage 1: stemmed full-text matches ranked by Mongo's text score
+	textFilter := bson.M{"$text": bson.M{"$search": query}}
+	for k, v := range filter {
+		textFilter[k] = v
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetLimit(searchCandidateLimit)
+
+	cursor, err := collection.Find(ctx, textFilter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var textHits []bson.M
+	if err = cursor.All(ctx, &textHits); err != nil {
+		return nil, err
+	}
+	for _, doc := range textHits {
+		id := fmt.Sprint(doc[idKey])
+		score, _ := doc["score"].(float64)
+		results[id] = doc
+		scores[id] = score
+	}
+
+	// Stage 2: candidates with a word starting like any term. They are ranked in the database before the limit
+	// is applied, so exact words and prefixes aren't crowded out by typo matches.
+	var or, rank bson.A
+	for _, term := range terms {
+		for i, field := range fields {
+			weight := 1
+			if i == 0 {
+				weight = 3
+			}
+			or = append(or, bson.M{field: bson.M{"$regex": candidatePattern(term), "$options": "i"}})
+
+			input := bson.M{"$ifNull": bson.A{"$" + field, ""}}
+			word := bson.M{"$regexMatch": bson.M{"input": input, "regex": `(^|\W)` + regexp.QuoteMeta(term) + `(\W|$)`, "options": "i"}}
+			prefix := bson.M{"$regexMatch": bson.M{"input": input, "regex": `(^|\W)` + regexp.QuoteMeta(term), "options": "i"}}
+			rank = append(rank, bson.M{"$cond": bson.A{word, 2 * weight, bson.M{"$cond": bson.A{prefix, weight, 0}}}})
+		}
+	}
+
+	candidateFilter := bson.M{"$or": or}
+	for k, v := range filter {
+		candidateFilter[k] = v
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: candidateFilter}},
+		{{Key: "$addFields", Value: bson.M{"search_rank": bson.M{"$add": rank}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "search_rank", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: searchCandidateLimit}},
+		{{Key: "$project", Value: bson.M{"search_rank": 0}}},
+	}
+
+	cursor, err = collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []bson.M
+	if err = cursor.All(ctx, &candidates); err != nil {
+		return nil, err
+	}
+	for _, doc := range candidates {
+		id := fmt.Sprint(doc[idKey])
+		if _, ok := results[id]; !ok {
+			results[id] = doc
+		}
+	}
+
+	// Fuzzy scores are added on top of the text score for every result
+	ranked := make([]bson.M, 0, len(results))
+	for id, doc := range results {
+		score := scores[id]
+		for i, field := range fields {
+			text, _ := doc[field].(string)
+			weight := 1.0
+			if i == 0 {
+				weight = 3.0
+			}
+			score += helpers.FuzzyScore(terms, text) * weight
+		}
+		if score <= 0 {
+			continue
+		}
+		doc["score"] = score
+		ranked = append(ranked, doc)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i]["score"].(float64) > ranked[j]["score"].(float64)
+	})
+
+	return ranked, nil
+}
+
+// candidatePattern matches words that may score for the term. Short terms must start the word exactly;
+// longer ones may start with one edit of their first runes: a rune replaced, missing or added. Terms that
+// allow two typos also match when both typos fall in those first runes and the runes after them are intact.
+func candidatePattern(term string) string {
+	r := []rune(term)
+	typos := helpers.MaxTypos(term)
+	if typos == 0 {
+		return `(^|\W)` + regexp.QuoteMeta(term)
+	}
+
+	quote := func(r []rune) string { return regexp.QuoteMeta(string(r)) }
+	head := r[:min(len(r), searchTypoPrefixLen)]
+	var variants []string
+	for i := range head {
+		variants = append(variants,
+			quote(head[:i])+`\w`+quote(head[i+1:]), // replaced
+			quote(head[:i])+quote(head[i+1:]),      // missing
+			quote(head[:i])+`\w`+quote(head[i:]),   // added
+		)
+	}
+	if typos > 1 && len(r) >= 2*searchTypoPrefixLen {
+		n := searchTypoPrefixLen
+		variants = append(variants, fmt.Sprintf(`\w{%d,%d}`, n-typos, n+typos)+quote(r[n:2*n]))
+	}
+	return `(^|\W)(` + strings.Join(variants, "|") + `)`
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCandidatePattern(t *testing.T) {
+	tests := []struct {
+		term, text string
+		want       bool
+	}{
+		{"tea", "Iced tea", true},
+		{"tea", "Teapot special", true},
+		{"tea", "Steak", false},
+		{"capucino", "Cappuccino", true},
+		{"cappucino", "Cappuccino", true},
+		{"kapuccino", "Cappuccino", true},
+		{"macchiato", "Cappuccino", false},
+		{"cpauccino", "Cappuccino", true},
+		{"apuccino", "Cappuccino", true},
+		{"burgre", "Cheese burger", true},
+		{"burgre", "Banana bread", false},
+		{"burgre", "Bun", false},
+	}
+	for _, tt := range tests {
+		re := regexp.MustCompile("(?i)" + candidatePattern(tt.term))
+		if got := re.MatchString(tt.text); got != tt.want {
+			t.Errorf("candidatePattern(%q) on %q = %v, want %v", tt.term, tt.text, got, tt.want)
+		}
+	}
+}
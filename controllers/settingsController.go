@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Get the restaurant settings
+func GetSettings() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var settings models.Settings
+		err := database.SettingsCollection.FindOne(ctx, bson.M{"settings_id": database.RestaurantSettingsID}).Decode(&settings)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Settings not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+// Update the restaurant settings (currency and rounding rule)
+func UpdateSettings() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var settings models.Settings
+		filter := bson.M{"settings_id": database.RestaurantSettingsID}
+		if err := database.SettingsCollection.FindOne(ctx, filter).Decode(&settings); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Settings not found"})
+			return
+		}
+
+		var input models.Settings
+		if err := c.BindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if input.Currency != "" {
+			input.Currency = strings.ToUpper(input.Currency)
+			// Stored amounts are in the old currency's minor units and can't be reinterpreted
+			if input.Currency != settings.Currency {
+				count, err := database.FoodCollection.CountDocuments(ctx, bson.M{})
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking existing prices"})
+					return
+				}
+				if count > 0 {
+					c.JSON(http.StatusConflict, gin.H{"error": "Currency cannot be changed once food items have prices"})
+					return
+				}
+			}
+			settings.Currency = input.Currency
+		}
+		if input.RoundingMode != "" {
+			settings.RoundingMode = input.RoundingMode
+		}
+
+		validationErr := helpers.Validate.Struct(settings)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		settings.UpdatedAt = time.Now()
+		update := bson.D{{Key: "$set", Value: bson.D{
+			{Key: "currency", Value: settings.Currency},
+			{Key: "rounding_mode", Value: settings.RoundingMode},
+			{Key: "updated_at", Value: settings.UpdatedAt},
+		}}}
+		if _, err := database.SettingsCollection.UpdateOne(ctx, filter, update); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Settings update failed"})
+			return
+		}
+
+		if err := database.ApplySettings(settings); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, settings)
+	}
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrOverCapacity is returned when a party doesn't fit a table and no manager overrode the check
+var ErrOverCapacity = errors.New("party size is outside the table's capacity")
+
+// ErrDuplicateTableNumber is returned when another table in the same area already has the number
+var ErrDuplicateTableNumber = errors.New("table number is already used in this area")
+
+// capacityOverrideRoles lists the roles allowed to seat or book a party outside a table's capacity
+var capacityOverrideRoles = map[string]bool{"admin": true, "manager": true}
+
+// tableCapacity returns the smallest and largest party a table takes. Without explicit limits a table takes
+// one guest up to its number of guests; a max of 0 means the table has no known limit.
+func tableCapacity(table models.Table) (int, int) {
+	minGuests, maxGuests := 1, 0
+	if table.MinCapacity != nil {
+		minGuests = *table.MinCapacity
+	}
+	if table.MaxCapacity != nil {
+		maxGuests = *table.MaxCapacity
+	} else if table.NumberOfGuests != nil {
+		maxGuests = *table.NumberOfGuests
+	}
+	return minGuests, maxGuests
+}
+
+// tableFits reports whether a party of the given size is within the table's capacity
+func tableFits(table models.Table, partySize int) bool {
+	minGuests, maxGuests := tableCapacity(table)
+	return partySize >= minGuests && (maxGuests == 0 || partySize <= maxGuests)
+}
+
+// checkTableCapacity fails with ErrOverCapacity when the party doesn't fit, unless the check was overridden
+func checkTableCapacity(table models.Table, partySize int, override bool) error {
+	if override || tableFits(table, partySize) {
+		return nil
+	}
+	minGuests, maxGuests := tableCapacity(table)
+	return fmt.Errorf("%w: table %d takes %d to %d guests, party of %d", ErrOverCapacity, intValue(table.TableNumber), minGuests, maxGuests, partySize)
+}
+
+// authorizeSeating checks that a party fits the table it is being seated at. Only managers may override
+// a failed check; the overriding user is returned. It writes the error response and returns false when
+// the party can't be seated.
+func authorizeSeating(ctx context.Context, c *gin.Context, table models.Table, partySize int, override bool) (*string, bool) {
+	if err := checkTableCapacity(table, partySize, override); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if !override || tableFits(table, partySize) {
+		return nil, true
+	}
+	uid := c.GetString("uid")
+	if !canOverrideCapacity(ctx, uid) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only managers can override table capacity"})
+		return nil, false
+	}
+	return &uid, true
+}
+
+// validateTableCapacity checks that a table's capacity limits are consistent
+func validateTableCapacity(table models.Table) error {
+	minGuests, maxGuests := tableCapacity(table)
+	if maxGuests != 0 && minGuests > maxGuests {
+		return fmt.Errorf("min_capacity (%d) can't be larger than max_capacity (%d)", minGuests, maxGuests)
+	}
+	return nil
+}
+
+// canOverrideCapacity reports whether the user may override capacity checks. The role is read from the
+// database, like RequireRole does, so demotions apply straight away.
+func canOverrideCapacity(ctx context.Context, uid string) bool {
+	if uid == "" {
+		return false
+	}
+	var user models.User
+	if err := database.UserCollection.FindOne(ctx, bson.M{"user_id": uid}).Decode(&user); err != nil {
+		return false
+	}
+	return capacityOverrideRoles[user.Role]
+}
+
+// checkTableNumber fails with ErrDuplicateTableNumber when another permanent table in the area uses the number.
+// Temporary combined tables share the number of their primary table and are ignored.
+func checkTableNumber(ctx context.Context, areaID *string, number int, excludeTableID string) error {
+	filter := bson.M{
+		"table_number":  number,
+		"area_id":       areaID,
+		"combined_from": bson.M{"$exists": false},
+	}
+	if excludeTableID != "" {
+		filter["table_id"] = bson.M{"$ne": excludeTableID}
+	}
+
+	count, err := database.TableCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %d", ErrDuplicateTableNumber, number)
+	}
+	return nil
+}
+
+// mergeTableUpdate returns the table as it will look once the non-nil fields of the update are applied
+func mergeTableUpdate(table, update models.Table) models.Table {
+	if update.NumberOfGuests != nil {
+		table.NumberOfGuests = update.NumberOfGuests
+	}
+	if update.TableNumber != nil {
+		table.TableNumber = update.TableNumber
+	}
+	if update.MinCapacity != nil {
+		table.MinCapacity = update.MinCapacity
+	}
+	if update.MaxCapacity != nil {
+		table.MaxCapacity = update.MaxCapacity
+	}
+	if update.AreaID != nil {
+		table.AreaID = update.AreaID
+	}
+	return table
+}
+
+// tableNumberError maps table number conflicts to responses
+func tableNumberError(c *gin.Context, err error) {
+	if errors.Is(err, ErrDuplicateTableNumber) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while checking the table number"})
+}
+
+// intValue dereferences an optional int, returning 0 for nil
+func intValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"errors"
+	"golang-restaurant-management/models"
+	"testing"
+)
+
+func TestTableFits(t *testing.T) {
+	ptr := func(v int) *int { return &v }
+	tests := []struct {
+		name      string
+		table     models.Table
+		partySize int
+		want      bool
+	}{
+		{"within number of guests", models.Table{NumberOfGuests: ptr(4)}, 4, true},
+		{"above number of guests", models.Table{NumberOfGuests: ptr(4)}, 5, false},
+		{"max capacity wins over number of guests", models.Table{NumberOfGuests: ptr(4), MaxCapacity: ptr(6)}, 6, true},
+		{"below min capacity", models.Table{MinCapacity: ptr(3), MaxCapacity: ptr(6)}, 2, false},
+		{"at min capacity", models.Table{MinCapacity: ptr(3), MaxCapacity: ptr(6)}, 3, true},
+		{"no known limit", models.Table{}, 20, true},
+	}
+	for _, tt := range tests {
+		if got := tableFits(tt.table, tt.partySize); got != tt.want {
+			t.Errorf("%s: fits party of %d = %v, want %v", tt.name, tt.partySize, got, tt.want)
+		}
+	}
+}
+
+func TestCheckTableCapacity(t *testing.T) {
+	four := 4
+	table := models.Table{NumberOfGuests: &four}
+	if err := checkTableCapacity(table, 6, false); !errors.Is(err, ErrOverCapacity) {
+		t.Errorf("over capacity: %v, want ErrOverCapacity", err)
+	}
+	if err := checkTableCapacity(table, 6, true); err != nil {
+		t.Errorf("overridden: %v", err)
+	}
+	if err := checkTableCapacity(table, 2, false); err != nil {
+		t.Errorf("fitting party: %v", err)
+	}
+}
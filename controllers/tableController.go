@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
 	"golang-restaurant-management/helpers"
 	"golang-restaurant-management/models"
 	"log"
@@ -13,16 +15,58 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// Get all tables
+// Get all tables, optionally filtered by ?status=, ?area_id= and ?section_id=.
+// With ?view=floor the tables are grouped by floor area with their status and server on shift (?at=<RFC3339>).
 func GetTables() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
 
-		result, err := database.TableCollection.Find(ctx, bson.M{})
+		if c.Query("view") == "floor" {
+			at := time.Now()
+			if v := c.Query("at"); v != "" {
+				parsed, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid at timestamp, expected RFC3339"})
+					return
+				}
+				at = parsed
+			}
+
+			plan, err := FloorPlan(ctx, at, c.Query("area_id"))
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while building the floor plan"})
+				return
+			}
+
+			c.JSON(http.StatusOK, plan)
+			return
+		}
+
+		filter := bson.M{}
+		if areaID := c.Query("area_id"); areaID != "" {
+			filter["area_id"] = areaID
+		}
+		if sectionID := c.Query("section_id"); sectionID != "" {
+			filter["section_id"] = sectionID
+		}
+		if status := c.Query("status"); status != "" {
+			if _, ok := tableTransitions[status]; !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown table status: " + status})
+				return
+			}
+			filter["status"] = status
+			if status == TableAvailable {
+				// Tables without a status are available
+				filter["status"] = bson.M{"$in": bson.A{TableAvailable, nil, ""}}
+			}
+		}
+
+		result, err := database.TableCollection.Find(ctx, filter)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing table items"})
 			return
@@ -77,19 +121,47 @@ func CreateTable() gin.HandlerFunc {
 			return
 		}
 
+		if err := validateTableCapacity(table); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Placement on the floor plan must point at existing areas and sections
+		if table.AreaID != nil && !floorAreaExists(ctx, *table.AreaID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Floor area not found"})
+			return
+		}
+		if table.SectionID != nil && !sectionExists(ctx, *table.SectionID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Section not found"})
+			return
+		}
+
+		if err := checkTableNumber(ctx, table.AreaID, *table.TableNumber, ""); err != nil {
+			tableNumberError(c, err)
+			return
+		}
+
 		// Assign timestamps and IDs
 		table.CreatedAt = time.Now()
 		table.UpdatedAt = time.Now()
 		table.ID = primitive.NewObjectID()
 		table.TableID = table.ID.Hex()
+		table.Status = TableAvailable
+		table.StatusUpdatedAt = &table.CreatedAt
 
 		// Insert into DB
 		result, insertErr := database.TableCollection.InsertOne(ctx, table)
 		if insertErr != nil {
+			if mongo.IsDuplicateKeyError(insertErr) {
+				tableNumberError(c, ErrDuplicateTableNumber)
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Table could not be created"})
 			return
 		}
 
+		publishTableEvent(events.TableCreated, table)
+
 		c.JSON(http.StatusOK, result)
 	}
 }
@@ -120,6 +192,57 @@ func UpdateTable() gin.HandlerFunc {
 			updateObj = append(updateObj, bson.E{Key: "table_number", Value: table.TableNumber})
 		}
 
+		if table.MinCapacity != nil {
+			updateObj = append(updateObj, bson.E{Key: "min_capacity", Value: table.MinCapacity})
+		}
+
+		if table.MaxCapacity != nil {
+			updateObj = append(updateObj, bson.E{Key: "max_capacity", Value: table.MaxCapacity})
+		}
+
+		if table.AreaID != nil {
+			if !floorAreaExists(ctx, *table.AreaID) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Floor area not found"})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "area_id", Value: table.AreaID})
+		}
+
+		if table.SectionID != nil {
+			if !sectionExists(ctx, *table.SectionID) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Section not found"})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "section_id", Value: table.SectionID})
+		}
+
+		if table.Layout != nil {
+			if validationErr := helpers.Validate.Struct(table.Layout); validationErr != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "layout", Value: table.Layout})
+		}
+
+		// Number and capacity are checked against the table as it will be after the update
+		var existing models.Table
+		err := database.TableCollection.FindOne(ctx, bson.M{"table_id": tableID}).Decode(&existing)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Table update failed"})
+			return
+		}
+		merged := mergeTableUpdate(existing, table)
+		if err := validateTableCapacity(merged); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if merged.TableNumber != nil && len(existing.CombinedFrom) == 0 && (table.TableNumber != nil || table.AreaID != nil) {
+			if err := checkTableNumber(ctx, merged.AreaID, *merged.TableNumber, tableID); err != nil {
+				tableNumberError(c, err)
+				return
+			}
+		}
+
 		// Update timestamp
 		table.UpdatedAt = time.Now()
 		updateObj = append(updateObj, bson.E{Key: "updated_at", Value: table.UpdatedAt})
@@ -131,10 +254,16 @@ func UpdateTable() gin.HandlerFunc {
 
 		result, err := database.TableCollection.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: updateObj}}, &opt)
 		if err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				tableNumberError(c, ErrDuplicateTableNumber)
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Table update failed"})
 			return
 		}
 
+		publishTableChange(ctx, events.TableUpdated, tableID)
+
 		c.JSON(http.StatusOK, result)
 	}
 }
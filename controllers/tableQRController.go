@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-pdf/fpdf"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetTableQR renders the QR code for a table's signed guest link (?format=png|svg&size=256)
+func GetTableQR() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var table models.Table
+		err := database.TableCollection.FindOne(ctx, bson.M{"table_id": c.Param("table_id")}).Decode(&table)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+			return
+		}
+
+		size := helpers.DefaultQRSize
+		if v := c.Query("size"); v != "" {
+			size, err = strconv.Atoi(v)
+			if err != nil || size < helpers.MinQRSize || size > helpers.MaxQRSize {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("size must be between %d and %d", helpers.MinQRSize, helpers.MaxQRSize)})
+				return
+			}
+		}
+
+		link := tableLink(c, table)
+		switch c.DefaultQuery("format", "png") {
+		case "png":
+			code, err := helpers.QRCodePNG(link, size)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "QR code could not be rendered"})
+				return
+			}
+			c.Data(http.StatusOK, "image/png", code)
+		case "svg":
+			code, err := helpers.QRCodeSVG(link, size)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "QR code could not be rendered"})
+				return
+			}
+			c.Data(http.StatusOK, "image/svg+xml", code)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be png or svg"})
+		}
+	}
+}
+
+// GetTableQRSheet renders a printable A4 PDF with the QR code of every table
+func GetTableQRSheet() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		opts := options.Find().SetSort(bson.D{{Key: "table_number", Value: 1}})
+		result, err := database.TableCollection.Find(ctx, bson.M{}, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing tables"})
+			return
+		}
+		var tables []models.Table
+		if err = result.All(ctx, &tables); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing tables"})
+			return
+		}
+
+		// 3 x 4 stickers of 65mm per A4 page
+		const columns, rows, cell, codeSize, margin = 3, 4, 65.0, 50.0, 10.0
+		pdf := fpdf.New("P", "mm", "A4", "")
+		pdf.SetAutoPageBreak(false, 0)
+		pdf.SetFont("Helvetica", "B", 14)
+
+		for i, table := range tables {
+			if i%(columns*rows) == 0 {
+				pdf.AddPage()
+			}
+			slot := i % (columns * rows)
+			x := margin + float64(slot%columns)*cell
+			y := margin + float64(slot/columns)*cell
+
+			code, err := helpers.QRCodePNG(tableLink(c, table), 512)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "QR code could not be rendered"})
+				return
+			}
+			name := "table-" + table.TableID
+			imageOptions := fpdf.ImageOptions{ImageType: "PNG"}
+			pdf.RegisterImageOptionsReader(name, imageOptions, bytes.NewReader(code))
+			pdf.ImageOptions(name, x+(cell-codeSize)/2, y, codeSize, codeSize, false, imageOptions, 0, "")
+
+			label := "Table"
+			if table.TableNumber != nil {
+				label = "Table " + strconv.Itoa(*table.TableNumber)
+			}
+			pdf.SetXY(x, y+codeSize+1)
+			pdf.CellFormat(cell, 7, label, "", 0, "C", false, 0, "")
+		}
+		if len(tables) == 0 {
+			pdf.AddPage()
+			pdf.CellFormat(0, 10, "No tables", "", 0, "C", false, 0, "")
+		}
+
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "QR sheet could not be rendered"})
+			return
+		}
+
+		c.Header("Content-Disposition", `inline; filename="table-qr-codes.pdf"`)
+		c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+	}
+}
+
+// RotateTableQR invalidates the printed QR code of a single table
+func RotateTableQR() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{"table_id": c.Param("table_id")}
+		update := bson.M{"$inc": bson.M{"qr_version": 1}, "$set": bson.M{"updated_at": time.Now()}}
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+		var table models.Table
+		if err := database.TableCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&table); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"table_id": table.TableID, "qr_version": table.QRVersion, "url": tableLink(c, table)})
+	}
+}
+
+// RotateAllTableQR replaces the signing key, invalidating the QR codes of every table
+func RotateAllTableQR() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		if err := database.RotateTableQRKey(ctx); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "QR signing key could not be rotated"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "All table QR codes have been invalidated, reprint the QR sheet"})
+	}
+}
+
+// GetPublicTable resolves a signed table link from a QR code (no authentication required)
+func GetPublicTable() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		tableID := c.Param("table_id")
+		version, err := strconv.Atoi(c.Query("v"))
+		if err != nil || !helpers.VerifyTableLink(database.TableQRKey(), tableID, version, c.Query("sig")) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or expired table link"})
+			return
+		}
+
+		var table models.Table
+		if err := database.TableCollection.FindOne(ctx, bson.M{"table_id": tableID}).Decode(&table); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+			return
+		}
+		// Rotated tables keep their ID, so the signed version must still be the current one
+		if table.QRVersion != version {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or expired table link"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"table_id":     table.TableID,
+			"table_number": table.TableNumber,
+			"menus_url":    "/public/menus",
+		})
+	}
+}
+
+// tableLink builds the signed guest URL encoded in a table's QR code.
+// PUBLIC_BASE_URL points at the guest-facing site; it defaults to this API's own host.
+func tableLink(c *gin.Context, table models.Table) string {
+	base := strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/")
+	if base == "" {
+		scheme := "http"
+		if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		base = scheme + "://" + c.Request.Host
+	}
+
+	query := url.Values{}
+	query.Set("v", strconv.Itoa(table.QRVersion))
+	query.Set("sig", helpers.SignTableLink(database.TableQRKey(), table.TableID, table.QRVersion))
+
+	return base + "/public/tables/" + url.PathEscape(table.TableID) + "?" + query.Encode()
+}
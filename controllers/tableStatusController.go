@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Table lifecycle statuses
+const (
+	TableAvailable     = "available"
+	TableSeated        = "seated"
+	TableOrdered       = "ordered"
+	TableBillRequested = "bill_requested"
+	TableNeedsCleaning = "needs_cleaning"
+	TableReserved      = "reserved"
+	TableOutOfService  = "out_of_service"
+)
+
+// tableTransitions lists the statuses each status may move to
+var tableTransitions = map[string][]string{
+	TableAvailable:     {TableSeated, TableReserved, TableOutOfService},
+	TableReserved:      {TableSeated, TableAvailable, TableOutOfService},
+	TableSeated:        {TableOrdered, TableBillRequested, TableAvailable},
+	TableOrdered:       {TableBillRequested, TableNeedsCleaning},
+	TableBillRequested: {TableOrdered, TableNeedsCleaning},
+	TableNeedsCleaning: {TableAvailable, TableOutOfService},
+	TableOutOfService:  {TableAvailable},
+}
+
+// ErrInvalidTableTransition is returned when a table can't move from its current status to the requested one
+var ErrInvalidTableTransition = errors.New("invalid table status transition")
+
+// Struct to hold a requested status change
+type TableStatusPack struct {
+	Status           string `json:"status" validate:"required"`
+	PartySize        *int   `json:"party_size" validate:"omitempty,gte=1"` // Required when seating a party
+	CapacityOverride bool   `json:"capacity_override"`                     // Seat a party outside the table's capacity (managers only)
+}
+
+// UpdateTableStatus moves a table to a new status if the lifecycle allows it
+func UpdateTableStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var pack TableStatusPack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if _, ok := tableTransitions[pack.Status]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown table status: " + pack.Status})
+			return
+		}
+		validationErr := helpers.Validate.Struct(pack)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if pack.Status == TableSeated {
+			if pack.PartySize == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "party_size is required to seat a table"})
+				return
+			}
+			var table models.Table
+			if err := database.TableCollection.FindOne(ctx, bson.M{"table_id": c.Param("table_id")}).Decode(&table); err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+				return
+			}
+			if _, ok := authorizeSeating(ctx, c, table, *pack.PartySize, pack.CapacityOverride); !ok {
+				return
+			}
+		}
+
+		table, err := TransitionTable(ctx, c.Param("table_id"), pack.Status)
+		if err != nil {
+			switch {
+			case errors.Is(err, mongo.ErrNoDocuments):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+			case errors.Is(err, ErrInvalidTableTransition):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "allowed": tableTransitions[TableStatus(table)]})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Table status update failed"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"table": table, "allowed": tableTransitions[table.Status]})
+	}
+}
+
+// TransitionTable moves a table to the given status, failing with ErrInvalidTableTransition
+// when the lifecycle does not allow it. The update is conditional on the status that was read,
+// so concurrent changes can't skip a step. The returned table reflects the stored state.
+func TransitionTable(ctx context.Context, tableID, to string) (models.Table, error) {
+	table, changed, err := transitionTable(ctx, tableID, to)
+	if err == nil && changed {
+		publishTableEvent(events.TableStatusChanged, table)
+	}
+	return table, err
+}
+
+// transitionTable does the work of TransitionTable without publishing the change, for callers running
+// inside a transaction that publish once it has committed. It reports whether the status changed.
+func transitionTable(ctx context.Context, tableID, to string) (models.Table, bool, error) {
+	var table models.Table
+	if err := database.TableCollection.FindOne(ctx, bson.M{"table_id": tableID}).Decode(&table); err != nil {
+		return table, false, err
+	}
+
+	if table.MergedInto != nil {
+		return table, false, fmt.Errorf("%w: table is merged into %s", ErrInvalidTableTransition, *table.MergedInto)
+	}
+
+	from := TableStatus(table)
+	if from == to {
+		return table, false, nil
+	}
+	if !canTransitionTable(from, to) {
+		return table, false, fmt.Errorf("%w: %s -> %s", ErrInvalidTableTransition, from, to)
+	}
+
+	now := time.Now()
+	filter := bson.M{"table_id": tableID, "status": table.Status}
+	if table.Status == "" {
+		// Tables created before the lifecycle existed have no status field
+		filter["status"] = bson.M{"$in": bson.A{nil, ""}}
+	}
+	update := bson.M{"$set": bson.M{"status": to, "status_updated_at": now, "updated_at": now}}
+
+	result, err := database.TableCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return table, false, err
+	}
+	if result.MatchedCount == 0 {
+		return table, false, fmt.Errorf("%w: table status changed concurrently", ErrInvalidTableTransition)
+	}
+
+	table.Status = to
+	table.StatusUpdatedAt = &now
+	table.UpdatedAt = now
+	return table, true, nil
+}
+
+// advanceTable applies the automatic transitions triggered by an order or invoice, walking through
+// the given steps in order. It is best effort: steps the table's current status doesn't allow are skipped.
+func advanceTable(ctx context.Context, tableID *string, steps ...string) {
+	if tableID == nil || *tableID == "" {
+		return
+	}
+	for _, to := range steps {
+		_, err := TransitionTable(ctx, *tableID, to)
+		if err != nil && !errors.Is(err, ErrInvalidTableTransition) {
+			log.Printf("table %s: automatic transition to %s failed: %v", *tableID, to, err)
+			return
+		}
+	}
+}
+
+// TableStatus returns the status of a table, treating tables without one as available
+func TableStatus(table models.Table) string {
+	if table.Status == "" {
+		return TableAvailable
+	}
+	return table.Status
+}
+
+// canTransitionTable reports whether the lifecycle allows moving from one status to another
+func canTransitionTable(from, to string) bool {
+	for _, allowed := range tableTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import "testing"
+
+func TestCanTransitionTable(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{TableAvailable, TableSeated, true},
+		{TableAvailable, TableReserved, true},
+		{TableReserved, TableSeated, true},
+		{TableSeated, TableOrdered, true},
+		{TableOrdered, TableBillRequested, true},
+		{TableBillRequested, TableNeedsCleaning, true},
+		{TableNeedsCleaning, TableAvailable, true},
+		{TableOutOfService, TableAvailable, true},
+		{TableAvailable, TableOrdered, false},
+		{TableOrdered, TableAvailable, false},
+		{TableNeedsCleaning, TableSeated, false},
+		{TableOutOfService, TableSeated, false},
+		{"unknown", TableAvailable, false},
+	}
+	for _, tt := range tests {
+		if got := canTransitionTable(tt.from, tt.to); got != tt.want {
+			t.Errorf("%s -> %s = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
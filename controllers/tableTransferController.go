@@ -0,0 +1,755 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/events"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/repository"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Kinds of table transfers recorded in the history
+const (
+	TransferKindOrder = "order"
+	TransferKindItems = "items"
+	TransferKindMerge = "merge"
+	TransferKindSplit = "split"
+)
+
+// ErrTableMerge is returned when tables can't be merged or split in their current state
+var ErrTableMerge = errors.New("tables can't be merged or split")
+
+// ErrTransfer is returned when an order or its items can't be moved
+var ErrTransfer = errors.New("transfer not possible")
+
+// Struct to hold the tables to push together
+type MergeTablesPack struct {
+	TableIDs []string `json:"table_ids" validate:"required,min=2,unique"`
+	Reason   *string  `json:"reason"`
+}
+
+// Struct to hold where each order goes when a combined table is split (order_id -> table_id).
+// Orders not listed go back to the first of the merged tables.
+type SplitTablePack struct {
+	Orders map[string]string `json:"orders"`
+	Reason *string           `json:"reason"`
+}
+
+// Struct to hold the tables a combined table was split into and the orders each one got back
+type SplitTableResult struct {
+	TableIDs []string            `json:"table_ids"`
+	Orders   map[string][]string `json:"orders"`
+}
+
+// Struct to hold the destination of a whole order
+type OrderTransferPack struct {
+	TableID *string `json:"table_id" validate:"required"`
+	Reason  *string `json:"reason"`
+}
+
+// Struct to hold the items to move and their destination, either an open order or a table
+type OrderItemTransferPack struct {
+	OrderItemIDs []string `json:"order_item_ids" validate:"required,min=1,unique"`
+	TableID      *string  `json:"table_id" validate:"required_without=ToOrderID"`
+	ToOrderID    *string  `json:"to_order_id"`
+	Reason       *string  `json:"reason"`
+}
+
+// tableOccupancyRank orders the statuses a combined table can inherit from its parts
+var tableOccupancyRank = map[string]int{
+	TableAvailable: 0,
+	TableReserved:  1,
+	TableSeated:    2,
+	TableOrdered:   3,
+}
+
+// MergeTables pushes tables together into a temporary combined table that takes over their open orders
+func MergeTables() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var pack MergeTablesPack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(pack)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		uid := c.GetString("uid")
+		result, err := repository.Transaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+			cursor, err := database.TableCollection.Find(sc, bson.M{"table_id": bson.M{"$in": pack.TableIDs}})
+			if err != nil {
+				return nil, err
+			}
+			var found []models.Table
+			if err = cursor.All(sc, &found); err != nil {
+				return nil, err
+			}
+			if len(found) != len(pack.TableIDs) {
+				return nil, mongo.ErrNoDocuments
+			}
+			byID := map[string]models.Table{}
+			for _, table := range found {
+				byID[table.TableID] = table
+			}
+
+			// The first table is the primary one: the combined table takes its number and place
+			primary := byID[pack.TableIDs[0]]
+			now := time.Now()
+			guests, capacity := 0, 0
+			status := TableAvailable
+			for _, id := range pack.TableIDs {
+				table := byID[id]
+				if table.MergedInto != nil || len(table.CombinedFrom) > 0 {
+					return nil, fmt.Errorf("%w: table %s is already merged", ErrTableMerge, id)
+				}
+				rank, ok := tableOccupancyRank[TableStatus(table)]
+				if !ok {
+					return nil, fmt.Errorf("%w: table %s is %s", ErrTableMerge, id, TableStatus(table))
+				}
+				if rank > tableOccupancyRank[status] {
+					status = TableStatus(table)
+				}
+				if table.NumberOfGuests != nil {
+					guests += *table.NumberOfGuests
+				}
+				_, maxGuests := tableCapacity(table)
+				if maxGuests == 0 || capacity < 0 {
+					capacity = -1
+				} else {
+					capacity += maxGuests
+				}
+			}
+
+			// The combined table takes the sum of its parts, unless one of them has no known limit
+			var maxCapacity *int
+			if capacity > 0 {
+				maxCapacity = &capacity
+			}
+
+			combined := models.Table{
+				NumberOfGuests:  &guests,
+				MaxCapacity:     maxCapacity,
+				TableNumber:     primary.TableNumber,
+				Status:          status,
+				StatusUpdatedAt: &now,
+				AreaID:          primary.AreaID,
+				SectionID:       primary.SectionID,
+				Layout:          primary.Layout,
+				CombinedFrom:    pack.TableIDs,
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			}
+			combined.ID = primitive.NewObjectID()
+			combined.TableID = combined.ID.Hex()
+			if _, err := database.TableCollection.InsertOne(sc, combined); err != nil {
+				return nil, err
+			}
+
+			marked, err := database.TableCollection.UpdateMany(sc,
+				bson.M{"table_id": bson.M{"$in": pack.TableIDs}, "merged_into": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"merged_into": combined.TableID, "updated_at": now}},
+			)
+			if err != nil {
+				return nil, err
+			}
+			if int(marked.ModifiedCount) != len(pack.TableIDs) {
+				return nil, fmt.Errorf("%w: tables changed concurrently", ErrTableMerge)
+			}
+
+			for _, id := range pack.TableIDs {
+				orderIDs, err := moveOpenOrders(sc, id, combined.TableID)
+				if err != nil {
+					return nil, err
+				}
+				_, err = recordTransfer(sc, models.TableTransfer{
+					Kind:        TransferKindMerge,
+					FromTableID: &id,
+					ToTableID:   &combined.TableID,
+					OrderIDs:    orderIDs,
+					Reason:      pack.Reason,
+					CreatedBy:   uid,
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return combined, nil
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, mongo.ErrNoDocuments):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Some tables were not found"})
+			case errors.Is(err, ErrTableMerge):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Tables could not be merged"})
+			}
+			return
+		}
+
+		combined := result.(models.Table)
+		publishTableEvent(events.TableCreated, combined)
+		for _, id := range pack.TableIDs {
+			publishTableChange(ctx, events.TableUpdated, id)
+		}
+		if open, err := openOrders(ctx, combined.TableID); err == nil {
+			for _, order := range open {
+				publishOrderEvent(events.OrderUpdated, order)
+			}
+		}
+
+		c.JSON(http.StatusCreated, result)
+	}
+}
+
+// SplitTable breaks a combined table up again, handing its orders back to the original tables
+func SplitTable() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		// The body is optional: without it every order returns to the primary table
+		var pack SplitTablePack
+		if err := c.ShouldBindJSON(&pack); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tableID := c.Param("table_id")
+		uid := c.GetString("uid")
+		result, err := repository.Transaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+			var combined models.Table
+			if err := database.TableCollection.FindOne(sc, bson.M{"table_id": tableID}).Decode(&combined); err != nil {
+				return nil, err
+			}
+			if len(combined.CombinedFrom) == 0 {
+				return nil, fmt.Errorf("%w: table %s is not a combined table", ErrTableMerge, tableID)
+			}
+
+			parts := map[string]bool{}
+			for _, id := range combined.CombinedFrom {
+				parts[id] = true
+			}
+			for orderID, target := range pack.Orders {
+				if !parts[target] {
+					return nil, fmt.Errorf("%w: table %s was not part of the combined table (order %s)", ErrTableMerge, target, orderID)
+				}
+			}
+
+			cursor, err := database.OrderCollection.Find(sc, bson.M{"table_id": tableID})
+			if err != nil {
+				return nil, err
+			}
+			var orders []models.Order
+			if err = cursor.All(sc, &orders); err != nil {
+				return nil, err
+			}
+
+			primary := combined.CombinedFrom[0]
+			moved := map[string][]string{}
+			seen := map[string]bool{}
+			for _, order := range orders {
+				target, ok := pack.Orders[order.OrderID]
+				if !ok {
+					target = primary
+				}
+				moved[target] = append(moved[target], order.OrderID)
+				seen[order.OrderID] = true
+			}
+			for orderID := range pack.Orders {
+				if !seen[orderID] {
+					return nil, fmt.Errorf("%w: order %s is not on the combined table", ErrTableMerge, orderID)
+				}
+			}
+
+			// Tables that keep guests inherit the combined status, the rest need a wipe down
+			status := TableStatus(combined)
+			now := time.Now()
+			for _, id := range combined.CombinedFrom {
+				orderIDs := moved[id]
+				if orderIDs == nil {
+					orderIDs = []string{}
+				}
+				if len(orderIDs) > 0 {
+					_, err := database.OrderCollection.UpdateMany(sc,
+						bson.M{"order_id": bson.M{"$in": orderIDs}},
+						bson.M{"$set": bson.M{"table_id": id, "updated_at": now}},
+					)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				open, err := openOrders(sc, id)
+				if err != nil {
+					return nil, err
+				}
+				partStatus := status
+				if id != primary && len(open) == 0 {
+					partStatus = TableAvailable
+					if status != TableAvailable && status != TableReserved {
+						partStatus = TableNeedsCleaning
+					}
+				}
+				_, err = database.TableCollection.UpdateOne(sc,
+					bson.M{"table_id": id},
+					bson.M{
+						"$set":   bson.M{"status": partStatus, "status_updated_at": now, "updated_at": now},
+						"$unset": bson.M{"merged_into": ""},
+					},
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				from := tableID
+				target := id
+				_, err = recordTransfer(sc, models.TableTransfer{
+					Kind:        TransferKindSplit,
+					FromTableID: &from,
+					ToTableID:   &target,
+					OrderIDs:    orderIDs,
+					Reason:      pack.Reason,
+					CreatedBy:   uid,
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if _, err := database.TableCollection.DeleteOne(sc, bson.M{"table_id": tableID}); err != nil {
+				return nil, err
+			}
+
+			return SplitTableResult{TableIDs: combined.CombinedFrom, Orders: moved}, nil
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, mongo.ErrNoDocuments):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+			case errors.Is(err, ErrTableMerge):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Table could not be split"})
+			}
+			return
+		}
+
+		split := result.(SplitTableResult)
+		events.Publish(events.Event{Type: events.TableDeleted, ResourceID: tableID, TableID: tableID})
+		for _, id := range split.TableIDs {
+			publishTableChange(ctx, events.TableUpdated, id)
+		}
+		for _, orderIDs := range split.Orders {
+			for _, orderID := range orderIDs {
+				publishOrderChange(ctx, events.OrderUpdated, orderID)
+			}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// TransferOrder moves a whole open order to another table
+func TransferOrder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var pack OrderTransferPack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(pack)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		var order models.Order
+		if err := database.OrderCollection.FindOne(ctx, bson.M{"order_id": c.Param("order_id")}).Decode(&order); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+
+		target, err := resolveTable(ctx, *pack.TableID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+			return
+		}
+
+		transfer, err := moveOrder(ctx, order, target, pack.Reason, c.GetString("uid"))
+		if err != nil {
+			if errors.Is(err, ErrTransfer) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Order could not be transferred"})
+			return
+		}
+
+		c.JSON(http.StatusOK, transfer)
+	}
+}
+
+// TransferOrderItems moves individual items of an open order to another open order,
+// or to a table, where they join its latest open order or start a new one
+func TransferOrderItems() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var pack OrderItemTransferPack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(pack)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		var source models.Order
+		if err := database.OrderCollection.FindOne(ctx, bson.M{"order_id": c.Param("order_id")}).Decode(&source); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return
+		}
+		if paid, err := orderPaid(ctx, source); err != nil || paid {
+			c.JSON(http.StatusConflict, gin.H{"error": "Items of a paid, closed or cancelled order can't be moved"})
+			return
+		}
+
+		// Work out the destination order, creating one when the table has none open
+		var destination models.Order
+		if pack.ToOrderID != nil {
+			if err := database.OrderCollection.FindOne(ctx, bson.M{"order_id": *pack.ToOrderID}).Decode(&destination); err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Destination order not found"})
+				return
+			}
+			if paid, err := orderPaid(ctx, destination); err != nil || paid {
+				c.JSON(http.StatusConflict, gin.H{"error": "Items can't be moved into a paid, closed or cancelled order"})
+				return
+			}
+		} else {
+			target, err := resolveTable(ctx, *pack.TableID)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+				return
+			}
+			open, err := openOrders(ctx, target.TableID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while looking up the table's orders"})
+				return
+			}
+			for _, order := range open {
+				if order.OrderID != source.OrderID {
+					destination = order
+				}
+			}
+			if destination.OrderID == "" {
+				now := time.Now()
+				destination = models.Order{Type: OrderDineIn, TableID: &target.TableID, OrderDate: now, CreatedAt: now, UpdatedAt: now}
+				destination.ID = primitive.NewObjectID()
+				initOrderStatus(&destination, c.GetString("uid"))
+			}
+		}
+		if destination.OrderID == source.OrderID && destination.OrderID != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Items are already in this order"})
+			return
+		}
+
+		uid := c.GetString("uid")
+		newDestination := destination.OrderID == ""
+		result, err := repository.Transaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+			if newDestination {
+				destination.OrderID = destination.ID.Hex()
+				if err := repository.Orders().Insert(sc, &destination); err != nil {
+					return nil, err
+				}
+			}
+
+			moved, err := database.OrderItemCollection.UpdateMany(sc,
+				bson.M{"order_item_id": bson.M{"$in": pack.OrderItemIDs}, "order_id": source.OrderID},
+				bson.M{"$set": bson.M{"order_id": destination.OrderID, "updated_at": time.Now()}},
+			)
+			if err != nil {
+				return nil, err
+			}
+			if int(moved.MatchedCount) != len(pack.OrderItemIDs) {
+				return nil, fmt.Errorf("%w: some items are not part of order %s", ErrTransfer, source.OrderID)
+			}
+
+			return recordTransfer(sc, models.TableTransfer{
+				Kind:         TransferKindItems,
+				FromTableID:  source.TableID,
+				ToTableID:    destination.TableID,
+				OrderIDs:     []string{source.OrderID},
+				OrderItemIDs: pack.OrderItemIDs,
+				ToOrderID:    &destination.OrderID,
+				Reason:       pack.Reason,
+				CreatedBy:    uid,
+			})
+		})
+		if err != nil {
+			if errors.Is(err, ErrTransfer) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Order items could not be transferred"})
+			return
+		}
+
+		if newDestination {
+			publishOrderEvent(events.OrderCreated, destination)
+		}
+		cursor, err := database.OrderItemCollection.Find(ctx, bson.M{"order_item_id": bson.M{"$in": pack.OrderItemIDs}})
+		if err == nil {
+			var moved []models.OrderItem
+			if cursor.All(ctx, &moved) == nil {
+				for _, item := range moved {
+					publishOrderItemEvent(ctx, events.OrderItemUpdated, item)
+				}
+			}
+		}
+
+		advanceTable(ctx, destination.TableID, TableSeated, TableOrdered)
+
+		// An order already in the kitchen won't be submitted again, so items joining it are fired now
+		if OrderStatus(destination) == OrderInKitchen {
+			if _, err := fireKitchenTickets(ctx, destination); err != nil {
+				log.Printf("order %s: firing transferred items failed: %v", destination.OrderID, err)
+			}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// GetTableTransfers lists the moves to and from a table, newest first
+func GetTableTransfers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tableID := c.Param("table_id")
+		listTransfers(c, bson.M{"$or": bson.A{bson.M{"from_table_id": tableID}, bson.M{"to_table_id": tableID}}})
+	}
+}
+
+// GetOrderTransfers lists the moves an order took part in, newest first
+func GetOrderTransfers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID := c.Param("order_id")
+		listTransfers(c, bson.M{"$or": bson.A{bson.M{"order_ids": orderID}, bson.M{"to_order_id": orderID}}})
+	}
+}
+
+// listTransfers writes the transfers matching the filter to the response
+func listTransfers(c *gin.Context, filter bson.M) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	result, err := database.TransferCollection.Find(ctx, filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing transfers"})
+		return
+	}
+
+	transfers := []models.TableTransfer{}
+	if err = result.All(ctx, &transfers); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing transfers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+// moveOrder points an open order at another table and records the move. The table left behind
+// needs cleaning once it has no open orders.
+func moveOrder(ctx context.Context, order models.Order, target models.Table, reason *string, uid string) (models.TableTransfer, error) {
+	if OrderType(order) != OrderDineIn {
+		return models.TableTransfer{}, fmt.Errorf("%w: only dine_in orders are seated at a table", ErrTransfer)
+	}
+	if order.TableID != nil && *order.TableID == target.TableID {
+		return models.TableTransfer{}, fmt.Errorf("%w: order is already at table %s", ErrTransfer, target.TableID)
+	}
+	if TableStatus(target) == TableOutOfService {
+		return models.TableTransfer{}, fmt.Errorf("%w: table %s is out of service", ErrTransfer, target.TableID)
+	}
+	paid, err := orderPaid(ctx, order)
+	if err != nil {
+		return models.TableTransfer{}, err
+	}
+	if paid {
+		return models.TableTransfer{}, fmt.Errorf("%w: paid, closed or cancelled orders can't be moved", ErrTransfer)
+	}
+
+	result, err := repository.Transaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		_, err := database.OrderCollection.UpdateOne(sc,
+			bson.M{"order_id": order.OrderID},
+			bson.M{"$set": bson.M{"table_id": target.TableID, "updated_at": time.Now()}},
+		)
+		if err != nil {
+			return nil, err
+		}
+		return recordTransfer(sc, models.TableTransfer{
+			Kind:        TransferKindOrder,
+			FromTableID: order.TableID,
+			ToTableID:   &target.TableID,
+			OrderIDs:    []string{order.OrderID},
+			Reason:      reason,
+			CreatedBy:   uid,
+		})
+	})
+	if err != nil {
+		return models.TableTransfer{}, err
+	}
+
+	publishOrderChange(ctx, events.OrderUpdated, order.OrderID)
+	advanceTable(ctx, &target.TableID, TableSeated, TableOrdered)
+	if order.TableID != nil {
+		if open, err := openOrders(ctx, *order.TableID); err == nil && len(open) == 0 {
+			advanceTable(ctx, order.TableID, TableNeedsCleaning)
+		}
+	}
+
+	return result.(models.TableTransfer), nil
+}
+
+// moveOpenOrders points every open order of a table at another table and returns their IDs
+func moveOpenOrders(ctx context.Context, fromTableID, toTableID string) ([]string, error) {
+	open, err := openOrders(ctx, fromTableID)
+	if err != nil {
+		return nil, err
+	}
+	orderIDs := []string{}
+	for _, order := range open {
+		orderIDs = append(orderIDs, order.OrderID)
+	}
+	if len(orderIDs) == 0 {
+		return orderIDs, nil
+	}
+
+	_, err = database.OrderCollection.UpdateMany(ctx,
+		bson.M{"order_id": bson.M{"$in": orderIDs}},
+		bson.M{"$set": bson.M{"table_id": toTableID, "updated_at": time.Now()}},
+	)
+	return orderIDs, err
+}
+
+// openOrders returns the orders at a table that are not finished and have not been paid, oldest first
+func openOrders(ctx context.Context, tableID string) ([]models.Order, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "order_date", Value: 1}})
+	filter := bson.M{"table_id": tableID, "status": bson.M{"$nin": finishedOrderStatuses}}
+	cursor, err := database.OrderCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var orders []models.Order
+	if err = cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	orderIDs := []string{}
+	for _, order := range orders {
+		orderIDs = append(orderIDs, order.OrderID)
+	}
+	paid, err := paidOrders(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	open := []models.Order{}
+	for _, order := range orders {
+		if !paid[order.OrderID] {
+			open = append(open, order)
+		}
+	}
+	return open, nil
+}
+
+// orderPaid reports whether an order is finished or has a paid invoice
+func orderPaid(ctx context.Context, order models.Order) (bool, error) {
+	switch OrderStatus(order) {
+	case OrderPaid, OrderClosed, OrderCancelled:
+		return true, nil
+	}
+	paid, err := paidOrders(ctx, []string{order.OrderID})
+	return paid[order.OrderID], err
+}
+
+// paidOrders returns which of the given orders have a paid invoice
+func paidOrders(ctx context.Context, orderIDs []string) (map[string]bool, error) {
+	filter := bson.M{
+		"order_id":       bson.M{"$in": orderIDs},
+		"payment_status": bson.M{"$in": bson.A{"paid", "PAID"}},
+	}
+	cursor, err := database.InvoiceCollection.Find(ctx, filter, options.Find().SetProjection(bson.M{"order_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var invoices []models.Invoice
+	if err = cursor.All(ctx, &invoices); err != nil {
+		return nil, err
+	}
+
+	paid := map[string]bool{}
+	for _, invoice := range invoices {
+		paid[invoice.OrderID] = true
+	}
+	return paid, nil
+}
+
+// resolveTable loads a table, following it to the combined table when it has been merged
+func resolveTable(ctx context.Context, tableID string) (models.Table, error) {
+	var table models.Table
+	if err := database.TableCollection.FindOne(ctx, bson.M{"table_id": tableID}).Decode(&table); err != nil {
+		return table, err
+	}
+	if table.MergedInto == nil {
+		return table, nil
+	}
+
+	var combined models.Table
+	err := database.TableCollection.FindOne(ctx, bson.M{"table_id": *table.MergedInto}).Decode(&combined)
+	return combined, err
+}
+
+// recordTransfer appends a move to the transfer history
+func recordTransfer(ctx context.Context, transfer models.TableTransfer) (models.TableTransfer, error) {
+	if transfer.OrderIDs == nil {
+		transfer.OrderIDs = []string{}
+	}
+	transfer.CreatedAt = time.Now()
+	transfer.ID = primitive.NewObjectID()
+	transfer.TransferID = transfer.ID.Hex()
+
+	_, err := database.TransferCollection.InsertOne(ctx, transfer)
+	return transfer, err
+}
@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"context"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Struct to hold translation completeness for one locale
+type TranslationCoverage struct {
+	Locale         string   `json:"locale"`
+	FoodsTotal     int      `json:"foods_total"`
+	FoodsComplete  int      `json:"foods_complete"`
+	MenusTotal     int      `json:"menus_total"`
+	MenusComplete  int      `json:"menus_complete"`
+	Completeness   float64  `json:"completeness"`
+	MissingFoodIDs []string `json:"missing_food_ids"`
+	MissingMenuIDs []string `json:"missing_menu_ids"`
+}
+
+// Set (create or replace) a food translation
+func SetFoodTranslation() gin.HandlerFunc {
+	return setTranslation(func() *mongo.Collection { return database.FoodCollection }, "food_id", "Food item", checkDirectFoodEdit)
+}
+
+// Delete a food translation
+func DeleteFoodTranslation() gin.HandlerFunc {
+	return deleteTranslation(func() *mongo.Collection { return database.FoodCollection }, "food_id", "Food item", checkDirectFoodEdit)
+}
+
+// Set (create or replace) a menu translation
+func SetMenuTranslation() gin.HandlerFunc {
+	return setTranslation(func() *mongo.Collection { return database.MenuCollection }, "menu_id", "Menu", checkDirectMenuEdit)
+}
+
+// Delete a menu translation
+func DeleteMenuTranslation() gin.HandlerFunc {
+	return deleteTranslation(func() *mongo.Collection { return database.MenuCollection }, "menu_id", "Menu", checkDirectMenuEdit)
+}
+
+// setTranslation builds a handler storing translations.<locale> on the document identified by idKey.
+// Collections are resolved lazily because they are only initialised once main connects to Mongo.
+// checkEdit rejects documents whose content has to change through a menu draft.
+func setTranslation(collection func() *mongo.Collection, idKey, label string, checkEdit func(context.Context, string) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		locale := helpers.NormalizeLocale(c.Param("locale"))
+		if !helpers.IsSupportedLocale(locale) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported locale", "supported": helpers.SupportedLocales})
+			return
+		}
+
+		var translation models.Translation
+		if err := c.BindJSON(&translation); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(translation)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		if err := checkEdit(ctx, c.Param(idKey)); err != nil {
+			directEditError(c, err, label)
+			return
+		}
+
+		update := bson.D{{Key: "$set", Value: bson.D{
+			{Key: "translations." + locale, Value: translation},
+			{Key: "updated_at", Value: time.Now()},
+		}}}
+		result, err := collection().UpdateOne(ctx, bson.M{idKey: c.Param(idKey)}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Translation update failed"})
+			return
+		}
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": label + " not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"locale": locale, "translation": translation})
+	}
+}
+
+// deleteTranslation builds a handler removing translations.<locale> from the document identified by idKey
+func deleteTranslation(collection func() *mongo.Collection, idKey, label string, checkEdit func(context.Context, string) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		locale := helpers.NormalizeLocale(c.Param("locale"))
+		if !helpers.IsSupportedLocale(locale) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported locale", "supported": helpers.SupportedLocales})
+			return
+		}
+		if err := checkEdit(ctx, c.Param(idKey)); err != nil {
+			directEditError(c, err, label)
+			return
+		}
+
+		update := bson.D{
+			{Key: "$unset", Value: bson.D{{Key: "translations." + locale, Value: ""}}},
+			{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}},
+		}
+		result, err := collection().UpdateOne(ctx, bson.M{idKey: c.Param(idKey)}, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Translation delete failed"})
+			return
+		}
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": label + " not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Translation deleted successfully"})
+	}
+}
+
+// unsupportedTranslationLocale returns the first locale of the translations that isn't supported, or ""
+func unsupportedTranslationLocale(translations map[string]models.Translation) string {
+	for locale := range translations {
+		if !helpers.IsSupportedLocale(locale) {
+			return locale
+		}
+	}
+	return ""
+}
+
+// GetTranslationReport reports, per supported locale, how many foods and menus are fully translated
+func GetTranslationReport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		projection := options.Find().SetProjection(bson.M{
+			"food_id": 1, "menu_id": 1, "description": 1, "category": 1, "translations": 1,
+		})
+
+		var foods []models.Food
+		cursor, err := database.FoodCollection.Find(ctx, bson.M{}, projection)
+		if err == nil {
+			err = cursor.All(ctx, &foods)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing food items"})
+			return
+		}
+
+		var menus []models.Menu
+		cursor, err = database.MenuCollection.Find(ctx, bson.M{}, projection)
+		if err == nil {
+			err = cursor.All(ctx, &menus)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing menus"})
+			return
+		}
+
+		report := []TranslationCoverage{}
+		for _, locale := range helpers.SupportedLocales {
+			// Base fields are written in the default locale, so it is always complete
+			if locale == helpers.DefaultLocale {
+				continue
+			}
+
+			coverage := TranslationCoverage{
+				Locale:         locale,
+				FoodsTotal:     len(foods),
+				MenusTotal:     len(menus),
+				MissingFoodIDs: []string{},
+				MissingMenuIDs: []string{},
+			}
+			for _, food := range foods {
+				t, ok := food.Translations[locale]
+				if ok && t.Name != "" && (food.Description == nil || *food.Description == "" || t.Description != "") {
+					coverage.FoodsComplete++
+				} else {
+					coverage.MissingFoodIDs = append(coverage.MissingFoodIDs, food.FoodID)
+				}
+			}
+			for _, menu := range menus {
+				t, ok := menu.Translations[locale]
+				if ok && t.Name != "" && (menu.Category == "" || t.Category != "") {
+					coverage.MenusComplete++
+				} else {
+					coverage.MissingMenuIDs = append(coverage.MissingMenuIDs, menu.MenuID)
+				}
+			}
+
+			coverage.Completeness = 1
+			if total := coverage.FoodsTotal + coverage.MenusTotal; total > 0 {
+				coverage.Completeness = toFixed(float64(coverage.FoodsComplete+coverage.MenusComplete)/float64(total), 4)
+			}
+			report = append(report, coverage)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"default_locale": helpers.DefaultLocale, "locales": report})
+	}
+}
+
+// requestLocale negotiates the response locale from ?lang= or Accept-Language and advertises it
+func requestLocale(c *gin.Context) string {
+	locale := helpers.NegotiateLocale(c.Query("lang"), c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", locale)
+	c.Header("Vary", "Accept-Language")
+	return locale
+}
+
+// localizeDocument overlays translated fields on a raw document, falling back to the default locale
+// and finally to the untranslated base fields
+func localizeDocument(doc bson.M, locale string, fields ...string) {
+	translations, _ := doc["translations"].(bson.M)
+	for _, field := range fields {
+		for _, candidate := range []string{locale, helpers.DefaultLocale} {
+			t, _ := translations[candidate].(bson.M)
+			if value, ok := t[field].(string); ok && value != "" {
+				doc[field] = value
+				break
+			}
+		}
+	}
+	doc["locale"] = locale
+}
+
+// localizeFood overlays translated fields on a food item
+func localizeFood(food *models.Food, locale string) {
+	for _, candidate := range []string{locale, helpers.DefaultLocale} {
+		t, ok := food.Translations[candidate]
+		if !ok || t.Name == "" {
+			continue
+		}
+		name := t.Name
+		food.Name = &name
+		if t.Description != "" {
+			description := t.Description
+			food.Description = &description
+		}
+		return
+	}
+}
+
+// localizeMenu overlays translated fields on a menu
+func localizeMenu(menu *models.Menu, locale string) {
+	for _, candidate := range []string{locale, helpers.DefaultLocale} {
+		t, ok := menu.Translations[candidate]
+		if !ok || t.Name == "" {
+			continue
+		}
+		menu.Name = t.Name
+		if t.Category != "" {
+			menu.Category = t.Category
+		}
+		return
+	}
+}
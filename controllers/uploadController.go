@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/storage"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// storedImage holds the public URLs of an uploaded image and its thumbnail
+type storedImage struct {
+	URL          string
+	ThumbnailURL string
+}
+
+// UploadFoodImage stores a multipart image for a food item and generates its thumbnail
+func UploadFoodImage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		foodID := c.Param("food_id")
+		var food models.Food
+
+		err := database.FoodCollection.FindOne(ctx, bson.M{"food_id": foodID}).Decode(&food)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food item not found"})
+			return
+		}
+		// Images of foods on versioned menus change through the menu draft
+		if food.MenuID != nil {
+			if err := checkDirectMenuEdit(ctx, *food.MenuID); err != nil {
+				directEditError(c, err, "Menu")
+				return
+			}
+		}
+
+		image, status, err := storeUploadedImage(ctx, c, "foods/"+foodID)
+		if err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		update := bson.D{
+			{Key: "food_image", Value: image.URL},
+			{Key: "thumbnail", Value: image.ThumbnailURL},
+			{Key: "updated_at", Value: time.Now()},
+		}
+		_, err = database.FoodCollection.UpdateOne(ctx, bson.M{"food_id": foodID}, bson.D{{Key: "$set", Value: update}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Food image update failed"})
+			return
+		}
+
+		// Old files are only removed once the document points at the new ones
+		removeStoredFile(ctx, food.FoodImage)
+		removeStoredFile(ctx, food.Thumbnail)
+
+		c.JSON(http.StatusOK, gin.H{"image": image.URL, "thumbnail": image.ThumbnailURL})
+	}
+}
+
+// UploadAvatar stores a multipart avatar image for the authenticated user
+func UploadAvatar() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		userID := c.Param("user_id")
+		if c.GetString("uid") != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own avatar"})
+			return
+		}
+
+		var user models.User
+		err := database.UserCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&user)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		image, status, err := storeUploadedImage(ctx, c, "avatars/"+userID)
+		if err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		update := bson.D{
+			{Key: "avatar", Value: image.URL},
+			{Key: "avatar_thumbnail", Value: image.ThumbnailURL},
+			{Key: "updated_at", Value: time.Now()},
+		}
+		_, err = database.UserCollection.UpdateOne(ctx, bson.M{"user_id": userID}, bson.D{{Key: "$set", Value: update}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Avatar update failed"})
+			return
+		}
+
+		removeStoredFile(ctx, user.Avatar)
+		removeStoredFile(ctx, user.AvatarThumbnail)
+
+		c.JSON(http.StatusOK, gin.H{"avatar": image.URL, "avatar_thumbnail": image.ThumbnailURL})
+	}
+}
+
+// ServeUpload streams a stored file back to the client
+func ServeUpload() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		file, info, err := storage.Default.Open(ctx, c.Param("filepath"))
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+			return
+		}
+		defer file.Close()
+
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.Header("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.DataFromReader(http.StatusOK, info.Size, info.ContentType, file, nil)
+	}
+}
+
+// storeUploadedImage reads the "image" form file, validates it and saves the original and thumbnail under prefix.
+// It returns the HTTP status to use when an error is returned.
+func storeUploadedImage(ctx context.Context, c *gin.Context, prefix string) (*storedImage, int, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, helpers.MaxImageSize+1<<20)
+
+	header, err := c.FormFile("image")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("image must be at most %d MiB", helpers.MaxImageSize>>20)
+		}
+		return nil, http.StatusBadRequest, errors.New("multipart field 'image' is required")
+	}
+	if header.Size > helpers.MaxImageSize {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("image must be at most %d MiB", helpers.MaxImageSize>>20)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("could not read uploaded image")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, helpers.MaxImageSize+1))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("could not read uploaded image")
+	}
+	if len(data) > helpers.MaxImageSize {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("image must be at most %d MiB", helpers.MaxImageSize>>20)
+	}
+
+	processed, err := helpers.ProcessImage(data)
+	if err != nil {
+		if errors.Is(err, helpers.ErrUnsupportedImage) {
+			return nil, http.StatusUnsupportedMediaType, err
+		}
+		return nil, http.StatusBadRequest, err
+	}
+
+	name := primitive.NewObjectID().Hex()
+	url, err := storage.Default.Save(ctx, prefix+"/"+name+processed.Extension, bytes.NewReader(processed.Original), processed.ContentType)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.New("failed to store image")
+	}
+	thumbnailURL, err := storage.Default.Save(ctx, prefix+"/"+name+"_thumb.jpg", bytes.NewReader(processed.Thumbnail), "image/jpeg")
+	if err != nil {
+		removeStoredFile(ctx, &url)
+		return nil, http.StatusInternalServerError, errors.New("failed to store thumbnail")
+	}
+
+	return &storedImage{URL: url, ThumbnailURL: thumbnailURL}, http.StatusOK, nil
+}
+
+// removeStoredFile deletes a previously uploaded file; URLs not managed by the storage backend are ignored
+func removeStoredFile(ctx context.Context, url *string) {
+	if url == nil {
+		return
+	}
+	if key, ok := storage.Default.KeyFromURL(*url); ok {
+		_ = storage.Default.Delete(ctx, key)
+	}
+}
@@ -16,9 +16,6 @@ import (
 	"time"
 )
 
-// Initialize user collection
-var userCollection *mongo.Collection = database.OpenCollection(database.Client, "user")
-
 // Get all users with pagination
 func GetUsers() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -42,7 +39,7 @@ func GetUsers() gin.HandlerFunc {
 		skipStage := bson.D{{Key: "$skip", Value: startIndex}}
 		limitStage := bson.D{{Key: "$limit", Value: recordPerPage}}
 
-		result, err := userCollection.Aggregate(ctx, mongo.Pipeline{matchStage, skipStage, limitStage})
+		result, err := database.UserCollection.Aggregate(ctx, mongo.Pipeline{matchStage, skipStage, limitStage})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing users"})
 			return
@@ -66,7 +63,7 @@ func GetUser() gin.HandlerFunc {
 		userID := c.Param("user_id")
 		var user models.User
 
-		err := userCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&user)
+		err := database.UserCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&user)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found"})
 			return
@@ -90,6 +87,9 @@ func SignUp() gin.HandlerFunc {
 			return
 		}
 
+		// Self-registered users are always customers; staff roles are granted by an admin
+		user.Role = "customer"
+
 		// Validate user input
 		validationErr := helpers.Validate.Struct(user)
 		if validationErr != nil {
@@ -98,14 +98,14 @@ func SignUp() gin.HandlerFunc {
 		}
 
 		// Check if email already exists
-		emailCount, err := userCollection.CountDocuments(ctx, bson.M{"email": user.Email})
+		emailCount, err := database.UserCollection.CountDocuments(ctx, bson.M{"email": user.Email})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking email"})
 			return
 		}
 
 		// Check if phone number already exists
-		phoneCount, err := userCollection.CountDocuments(ctx, bson.M{"phone": user.Phone})
+		phoneCount, err := database.UserCollection.CountDocuments(ctx, bson.M{"phone": user.Phone})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking phone number"})
 			return
@@ -133,7 +133,7 @@ func SignUp() gin.HandlerFunc {
 		user.RefreshToken = &refreshToken
 
 		// Insert into DB
-		result, insertErr := userCollection.InsertOne(ctx, user)
+		result, insertErr := database.UserCollection.InsertOne(ctx, user)
 		if insertErr != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "User could not be created"})
 			return
@@ -143,6 +143,52 @@ func SignUp() gin.HandlerFunc {
 	}
 }
 
+// Struct to hold a requested role change
+type UserRolePack struct {
+	Role string `json:"role" validate:"required,oneof=admin manager staff customer"`
+}
+
+// UpdateUserRole changes the role of a user (admins only)
+func UpdateUserRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var pack UserRolePack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		validationErr := helpers.Validate.Struct(pack)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		// Admins can't demote themselves, so there is always an admin left to grant roles
+		userID := c.Param("user_id")
+		if userID == c.GetString("uid") {
+			c.JSON(http.StatusConflict, gin.H{"error": "You can't change your own role"})
+			return
+		}
+
+		result, err := database.UserCollection.UpdateOne(ctx,
+			bson.M{"user_id": userID},
+			bson.M{"$set": bson.M{"role": pack.Role, "updated_at": time.Now()}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Role update failed"})
+			return
+		}
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"user_id": userID, "role": pack.Role})
+	}
+}
+
 // User Login
 func Login() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -159,7 +205,7 @@ func Login() gin.HandlerFunc {
 		}
 
 		// Find user by email
-		err := userCollection.FindOne(ctx, bson.M{"email": user.Email}).Decode(&foundUser)
+		err := database.UserCollection.FindOne(ctx, bson.M{"email": user.Email}).Decode(&foundUser)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Incorrect email or password"})
 			return
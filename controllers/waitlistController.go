@@ -0,0 +1,502 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/helpers"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/notify"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Waitlist statuses
+const (
+	WaitlistWaiting  = "waiting"
+	WaitlistNotified = "notified"
+	WaitlistSeated   = "seated"
+	WaitlistLeft     = "left"
+)
+
+const (
+	DefaultTurnMinutes = 60 // Assumed table turn time until enough history exists
+	CleaningMinutes    = 5  // Time to turn a table that only needs cleaning
+	turnHistoryDays    = 30 // How far back seating durations are averaged
+)
+
+// Struct to hold the table a waiting party is seated at
+type WaitlistSeatPack struct {
+	TableID          string `json:"table_id" validate:"required"`
+	CapacityOverride bool   `json:"capacity_override"`
+}
+
+// Struct to hold a waitlist entry with its current estimate
+type WaitlistView struct {
+	models.WaitlistEntry
+	Position         int `json:"position"`
+	EstimatedMinutes int `json:"estimated_minutes"`
+}
+
+// GetWaitlist lists the parties still waiting, in arrival order, with their current wait estimates
+func GetWaitlist() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		queue, err := activeWaitlist(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while listing the waitlist"})
+			return
+		}
+		estimator, err := newWaitEstimator(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while estimating wait times"})
+			return
+		}
+
+		views := []WaitlistView{}
+		for i, entry := range queue {
+			views = append(views, WaitlistView{
+				WaitlistEntry:    entry,
+				Position:         i + 1,
+				EstimatedMinutes: estimator.estimate(*entry.PartySize, queue[:i]),
+			})
+		}
+
+		c.JSON(http.StatusOK, views)
+	}
+}
+
+// GetWaitEstimate quotes the wait for a new party of ?party_size= joining now
+func GetWaitEstimate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		partySize, err := strconv.Atoi(c.Query("party_size"))
+		if err != nil || partySize < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "party_size must be a positive number"})
+			return
+		}
+
+		minutes, ahead, err := quoteWait(ctx, partySize)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while estimating wait times"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"party_size": partySize, "parties_ahead": ahead, "estimated_minutes": minutes})
+	}
+}
+
+// JoinWaitlist adds a walk-in party to the queue and quotes its wait
+func JoinWaitlist() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var entry models.WaitlistEntry
+		if err := c.BindJSON(&entry); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		validationErr := helpers.Validate.Struct(entry)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		minutes, _, err := quoteWait(ctx, *entry.PartySize)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error occurred while estimating wait times"})
+			return
+		}
+
+		entry.ID = primitive.NewObjectID()
+		entry.EntryID = entry.ID.Hex()
+		entry.QuotedMinutes = minutes
+		entry.Status = WaitlistWaiting
+		entry.TableID = nil
+		entry.CreatedAt = time.Now()
+		entry.UpdatedAt = time.Now()
+
+		if _, err := database.WaitlistCollection.InsertOne(ctx, entry); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Party could not be added to the waitlist"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, entry)
+	}
+}
+
+// UpdateWaitlistEntry changes the name, size or phone of a waiting party
+func UpdateWaitlistEntry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var input models.WaitlistEntry
+		if err := c.BindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var updateObj primitive.D
+		if input.PartyName != nil {
+			updateObj = append(updateObj, bson.E{Key: "party_name", Value: input.PartyName})
+		}
+		if input.PartySize != nil {
+			if *input.PartySize < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "party_size must be a positive number"})
+				return
+			}
+			updateObj = append(updateObj, bson.E{Key: "party_size", Value: input.PartySize})
+		}
+		if input.Phone != nil {
+			updateObj = append(updateObj, bson.E{Key: "phone", Value: input.Phone})
+		}
+		updateObj = append(updateObj, bson.E{Key: "updated_at", Value: time.Now()})
+
+		filter := bson.M{"entry_id": c.Param("entry_id"), "status": bson.M{"$in": bson.A{WaitlistWaiting, WaitlistNotified}}}
+		result, err := database.WaitlistCollection.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: updateObj}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Waitlist update failed"})
+			return
+		}
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No waiting party found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// NotifyWaitlistEntry tells a waiting party that its table is ready
+func NotifyWaitlistEntry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		entryID := c.Param("entry_id")
+		var entry models.WaitlistEntry
+		filter := bson.M{"entry_id": entryID, "status": bson.M{"$in": bson.A{WaitlistWaiting, WaitlistNotified}}}
+		if err := database.WaitlistCollection.FindOne(ctx, filter).Decode(&entry); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No waiting party found"})
+			return
+		}
+		if entry.Phone == nil || *entry.Phone == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Party has no phone number to notify"})
+			return
+		}
+
+		message := notify.Message{
+			To:    *entry.Phone,
+			Body:  "Hi " + *entry.PartyName + ", your table is ready. Please come to the host stand.",
+			Topic: "waitlist.ready",
+		}
+		if err := notify.Default.Notify(ctx, message); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Notification could not be sent: " + err.Error()})
+			return
+		}
+
+		now := time.Now()
+		update := bson.M{"$set": bson.M{"status": WaitlistNotified, "notified_at": now, "updated_at": now}}
+		if _, err := database.WaitlistCollection.UpdateOne(ctx, bson.M{"entry_id": entryID}, update); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Waitlist update failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Party notified", "notified_at": now})
+	}
+}
+
+// SeatWaitlistEntry seats a waiting party at a table that fits it
+func SeatWaitlistEntry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		var pack WaitlistSeatPack
+		if err := c.BindJSON(&pack); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		validationErr := helpers.Validate.Struct(pack)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+			return
+		}
+
+		entryID := c.Param("entry_id")
+		var entry models.WaitlistEntry
+		filter := bson.M{"entry_id": entryID, "status": bson.M{"$in": bson.A{WaitlistWaiting, WaitlistNotified}}}
+		if err := database.WaitlistCollection.FindOne(ctx, filter).Decode(&entry); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No waiting party found"})
+			return
+		}
+
+		var table models.Table
+		if err := database.TableCollection.FindOne(ctx, bson.M{"table_id": pack.TableID}).Decode(&table); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Table not found"})
+			return
+		}
+		overriddenBy, ok := authorizeSeating(ctx, c, table, *entry.PartySize, pack.CapacityOverride)
+		if !ok {
+			return
+		}
+		entry.OverriddenBy = overriddenBy
+
+		if _, err := TransitionTable(ctx, table.TableID, TableSeated); err != nil {
+			if errors.Is(err, ErrInvalidTableTransition) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Table is not ready for seating: " + err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Table status update failed"})
+			return
+		}
+
+		now := time.Now()
+		update := bson.M{"$set": bson.M{"status": WaitlistSeated, "table_id": table.TableID, "seated_at": now, "updated_at": now, "overridden_by": entry.OverriddenBy}}
+		if _, err := database.WaitlistCollection.UpdateOne(ctx, filter, update); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Waitlist update failed"})
+			return
+		}
+		entry.Status = WaitlistSeated
+		entry.TableID = &table.TableID
+		entry.SeatedAt = &now
+		entry.UpdatedAt = now
+
+		c.JSON(http.StatusOK, entry)
+	}
+}
+
+// LeaveWaitlist removes a party that gave up waiting
+func LeaveWaitlist() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+		defer cancel()
+
+		filter := bson.M{"entry_id": c.Param("entry_id"), "status": bson.M{"$in": bson.A{WaitlistWaiting, WaitlistNotified}}}
+		update := bson.M{"$set": bson.M{"status": WaitlistLeft, "updated_at": time.Now()}}
+
+		result, err := database.WaitlistCollection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Waitlist update failed"})
+			return
+		}
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No waiting party found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Party removed from the waitlist"})
+	}
+}
+
+// quoteWait estimates the wait of a party joining the back of the queue now
+func quoteWait(ctx context.Context, partySize int) (int, int, error) {
+	queue, err := activeWaitlist(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	estimator, err := newWaitEstimator(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return estimator.estimate(partySize, queue), len(queue), nil
+}
+
+// activeWaitlist loads the parties still waiting, in arrival order
+func activeWaitlist(ctx context.Context) ([]models.WaitlistEntry, error) {
+	filter := bson.M{"status": bson.M{"$in": bson.A{WaitlistWaiting, WaitlistNotified}}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := database.WaitlistCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	queue := []models.WaitlistEntry{}
+	if err = cursor.All(ctx, &queue); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// waitEstimator predicts when tables free up from their status and the average table turn time
+type waitEstimator struct {
+	now    time.Time
+	turn   time.Duration
+	tables []tableRelease
+}
+
+// tableRelease is a table and the moment it is expected to be free
+type tableRelease struct {
+	table  models.Table
+	freeAt time.Time
+}
+
+// newWaitEstimator snapshots table occupancy and the historical turn time
+func newWaitEstimator(ctx context.Context) (*waitEstimator, error) {
+	turn, err := averageTurnTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	estimator := &waitEstimator{now: time.Now(), turn: turn}
+
+	cursor, err := database.TableCollection.Find(ctx, bson.M{"status": bson.M{"$ne": TableOutOfService}, "merged_into": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+	var tables []models.Table
+	if err = cursor.All(ctx, &tables); err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		if _, maxGuests := tableCapacity(table); maxGuests == 0 {
+			continue
+		}
+		release := tableRelease{table: table, freeAt: estimator.now}
+
+		switch TableStatus(table) {
+		case TableNeedsCleaning:
+			release.freeAt = estimator.now.Add(CleaningMinutes * time.Minute)
+		case TableSeated, TableOrdered, TableBillRequested:
+			seatedAt, err := tableSeatedAt(ctx, table)
+			if err != nil {
+				return nil, err
+			}
+			release.freeAt = seatedAt.Add(turn + CleaningMinutes*time.Minute)
+			// Parties staying longer than average are expected to leave soon, not in the past
+			if earliest := estimator.now.Add(CleaningMinutes * time.Minute); release.freeAt.Before(earliest) {
+				release.freeAt = earliest
+			}
+		}
+		estimator.tables = append(estimator.tables, release)
+	}
+
+	return estimator, nil
+}
+
+// estimate returns the expected wait in minutes for a party queued behind the given parties, or -1 when
+// no table seats the party. Parties ahead are seated in turn at the fitting table that frees up first and keep
+// it for a turn, so only those competing for tables this party could use push it back.
+func (e *waitEstimator) estimate(partySize int, ahead []models.WaitlistEntry) int {
+	freeAt := make([]time.Time, len(e.tables))
+	for i, table := range e.tables {
+		freeAt[i] = table.freeAt
+	}
+	firstFree := func(size int) int {
+		best := -1
+		for i, table := range e.tables {
+			if tableFits(table.table, size) && (best < 0 || freeAt[i].Before(freeAt[best])) {
+				best = i
+			}
+		}
+		return best
+	}
+
+	for _, party := range ahead {
+		if party.PartySize == nil {
+			continue
+		}
+		i := firstFree(*party.PartySize)
+		if i < 0 {
+			continue
+		}
+		seatedAt := freeAt[i]
+		if seatedAt.Before(e.now) {
+			seatedAt = e.now
+		}
+		freeAt[i] = seatedAt.Add(e.turn + CleaningMinutes*time.Minute)
+	}
+
+	i := firstFree(partySize)
+	if i < 0 {
+		return -1
+	}
+	wait := freeAt[i].Sub(e.now)
+	if wait < 0 {
+		return 0
+	}
+	return int(math.Ceil(wait.Minutes()))
+}
+
+// tableSeatedAt returns when the current party sat down: the status change for parties that haven't
+// ordered yet, otherwise the latest order on the table
+func tableSeatedAt(ctx context.Context, table models.Table) (time.Time, error) {
+	if TableStatus(table) == TableSeated && table.StatusUpdatedAt != nil {
+		return *table.StatusUpdatedAt, nil
+	}
+
+	var order models.Order
+	opts := options.FindOne().SetSort(bson.D{{Key: "order_date", Value: -1}})
+	err := database.OrderCollection.FindOne(ctx, bson.M{"table_id": table.TableID}, opts).Decode(&order)
+	if err == nil {
+		return order.OrderDate, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, err
+	}
+
+	if table.StatusUpdatedAt != nil {
+		return *table.StatusUpdatedAt, nil
+	}
+	return time.Now(), nil
+}
+
+// averageTurnTime averages how long dine-in parties stayed over the recent history, from the order being
+// opened when they sat down to it being paid, both read from the order's status history.
+// Outliers (forgotten bills, instant payments) are ignored.
+func averageTurnTime(ctx context.Context) (time.Duration, error) {
+	since := time.Now().AddDate(0, 0, -turnHistoryDays)
+	paid := bson.M{"$filter": bson.M{
+		"input": "$status_history",
+		"as":    "change",
+		"cond":  bson.M{"$eq": bson.A{"$$change.to", OrderPaid}},
+	}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"table_id":       bson.M{"$ne": nil},
+			"status_history": bson.M{"$elemMatch": bson.M{"to": OrderPaid, "at": bson.M{"$gte": since}}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"seated_at": bson.M{"$arrayElemAt": bson.A{"$status_history.at", 0}},
+			"paid_at":   bson.M{"$arrayElemAt": bson.A{bson.M{"$map": bson.M{"input": paid, "in": "$$this.at"}}, 0}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"minutes": bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{"$paid_at", "$seated_at"}}, 60000}},
+		}}},
+		{{Key: "$match", Value: bson.M{"minutes": bson.M{"$gte": 10, "$lte": 240}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "minutes": bson.M{"$avg": "$minutes"}, "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := database.OrderCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	var stats []struct {
+		Minutes float64 `bson:"minutes"`
+		Count   int     `bson:"count"`
+	}
+	if err = cursor.All(ctx, &stats); err != nil {
+		return 0, err
+	}
+
+	// A handful of visits says little about a typical evening
+	if len(stats) == 0 || stats[0].Count < 5 {
+		return DefaultTurnMinutes * time.Minute, nil
+	}
+	return time.Duration(stats[0].Minutes * float64(time.Minute)), nil
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"golang-restaurant-management/models"
+	"testing"
+	"time"
+)
+
+func TestWaitEstimate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)
+	table := func(minGuests, maxGuests int, freeIn time.Duration) tableRelease {
+		return tableRelease{
+			table:  models.Table{MinCapacity: &minGuests, MaxCapacity: &maxGuests},
+			freeAt: now.Add(freeIn),
+		}
+	}
+	party := func(size int) models.WaitlistEntry {
+		return models.WaitlistEntry{PartySize: &size}
+	}
+	turn := time.Hour
+	// A party seated now keeps its table for a turn plus cleaning
+	seating := int((turn + CleaningMinutes*time.Minute).Minutes())
+
+	tests := []struct {
+		name      string
+		tables    []tableRelease
+		partySize int
+		ahead     []models.WaitlistEntry
+		want      int
+	}{
+		{"no table seats the party", []tableRelease{table(1, 4, 0)}, 6, nil, -1},
+		{"free table", []tableRelease{table(1, 4, 0)}, 2, nil, 0},
+		{"table frees up later", []tableRelease{table(1, 4, 20*time.Minute)}, 2, nil, 20},
+		{"larger party ahead takes the shared table", []tableRelease{table(1, 6, 0)}, 4, []models.WaitlistEntry{party(6)}, seating},
+		{"smaller party ahead takes the shared table", []tableRelease{table(1, 6, 0)}, 4, []models.WaitlistEntry{party(2)}, seating},
+		{"party ahead needs another table", []tableRelease{table(1, 2, 0), table(5, 8, 0)}, 6, []models.WaitlistEntry{party(2)}, 0},
+		{"party ahead that fits nowhere", []tableRelease{table(1, 4, 0)}, 4, []models.WaitlistEntry{party(10)}, 0},
+		{"party ahead takes the table freeing first", []tableRelease{table(1, 4, 0), table(1, 4, 30*time.Minute)}, 2, []models.WaitlistEntry{party(3)}, 30},
+		{"two parties ahead on one table", []tableRelease{table(1, 4, 0)}, 2, []models.WaitlistEntry{party(2), party(4)}, 2 * seating},
+	}
+	for _, tt := range tests {
+		estimator := &waitEstimator{now: now, turn: turn, tables: tt.tables}
+		if got := estimator.estimate(tt.partySize, tt.ahead); got != tt.want {
+			t.Errorf("%s: estimate = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -11,6 +11,23 @@ var (
 	FoodCollection    *mongo.Collection
 	InvoiceCollection *mongo.Collection
 	OrderItemCollection *mongo.Collection
+	FoodPriceCollection *mongo.Collection
+	BundleCollection    *mongo.Collection
+	UserCollection      *mongo.Collection
+	SettingsCollection  *mongo.Collection
+	MigrationCollection *mongo.Collection
+	CategoryCollection  *mongo.Collection
+	MenuVersionCollection *mongo.Collection
+	PricingRuleCollection *mongo.Collection
+	ReservationCollection *mongo.Collection
+	WaitlistCollection    *mongo.Collection
+	FloorAreaCollection   *mongo.Collection
+	SectionCollection     *mongo.Collection
+	AssignmentCollection  *mongo.Collection
+	TransferCollection    *mongo.Collection
+	IdempotencyCollection *mongo.Collection
+	KitchenStationCollection *mongo.Collection
+	KitchenTicketCollection  *mongo.Collection
 )
 
 // func InitCollections(client *mongo.Client) {
@@ -29,5 +46,22 @@ func InitCollections(client *mongo.Client) {
     FoodCollection = OpenCollection(client, "food")
     InvoiceCollection = OpenCollection(client, "invoice")
     OrderItemCollection = OpenCollection(client, "orderItem")
+    FoodPriceCollection = OpenCollection(client, "foodPrice")
+    BundleCollection = OpenCollection(client, "bundle")
+    UserCollection = OpenCollection(client, "user")
+    SettingsCollection = OpenCollection(client, "settings")
+    MigrationCollection = OpenCollection(client, "migrations")
+    CategoryCollection = OpenCollection(client, "category")
+    MenuVersionCollection = OpenCollection(client, "menuVersion")
+    PricingRuleCollection = OpenCollection(client, "pricingRule")
+    ReservationCollection = OpenCollection(client, "reservation")
+    WaitlistCollection = OpenCollection(client, "waitlist")
+    FloorAreaCollection = OpenCollection(client, "floorArea")
+    SectionCollection = OpenCollection(client, "section")
+    AssignmentCollection = OpenCollection(client, "sectionAssignment")
+    TransferCollection = OpenCollection(client, "tableTransfer")
+    IdempotencyCollection = OpenCollection(client, "idempotencyKey")
+    KitchenStationCollection = OpenCollection(client, "kitchenStation")
+    KitchenTicketCollection = OpenCollection(client, "kitchenTicket")
 }
 
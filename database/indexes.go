@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateIndexes makes sure every index the API relies on exists.
+// It is safe to call on every startup: Mongo ignores identical index definitions.
+func CreateIndexes(ctx context.Context) error {
+	// Text index used by GET /search on foods
+	_, err := FoodCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "name", Value: "text"},
+			{Key: "description", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("food_text").
+			SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "description", Value: 2}}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create food text index: %w", err)
+	}
+
+	// Text index used by GET /search on menus
+	_, err = MenuCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "name", Value: "text"},
+			{Key: "category", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("menu_text").
+			SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "category", Value: 5}}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create menu text index: %w", err)
+	}
+
+	// Price lookups always resolve the latest record effective at a given time; versions are unique per food,
+	// and the scheduler looks for prices that took effect but were not yet copied onto their food
+	_, err = FoodPriceCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "food_id", Value: 1}, {Key: "effective_at", Value: -1}},
+			Options: options.Index().SetName("food_price_effective"),
+		},
+		{
+			Keys:    bson.D{{Key: "food_id", Value: 1}, {Key: "version", Value: 1}},
+			Options: options.Index().SetName("food_price_version").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "applied_at", Value: 1}, {Key: "effective_at", Value: 1}},
+			Options: options.Index().SetName("food_price_pending"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create food price indexes (duplicate price versions?): %w", err)
+	}
+
+	// Menu trees load categories per menu in display order
+	_, err = CategoryCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "menu_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "position", Value: 1}},
+		Options: options.Index().SetName("category_tree"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create category index: %w", err)
+	}
+
+	// At most one open draft per menu, and version numbers are unique per menu
+	_, err = MenuVersionCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "menu_id", Value: 1}},
+			Options: options.Index().SetName("menu_version_single_draft").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": "draft"}),
+		},
+		{
+			Keys: bson.D{{Key: "menu_id", Value: 1}, {Key: "version", Value: -1}},
+			Options: options.Index().SetName("menu_version_number").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"version": bson.M{"$gt": 0}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create menu version indexes: %w", err)
+	}
+
+	// Order pricing looks up the rules targeting a food, its menu or its category
+	_, err = PricingRuleCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "scope", Value: 1}, {Key: "target_ids", Value: 1}},
+		Options: options.Index().SetName("pricing_rule_target"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create pricing rule index: %w", err)
+	}
+
+	// Conflict checks look for overlapping bookings per table
+	_, err = ReservationCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "table_id", Value: 1}, {Key: "reserved_at", Value: 1}, {Key: "ends_at", Value: 1}},
+			Options: options.Index().SetName("reservation_table_window"),
+		},
+		{
+			Keys:    bson.D{{Key: "reserved_at", Value: 1}},
+			Options: options.Index().SetName("reservation_time"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reservation indexes: %w", err)
+	}
+
+	// The waitlist is read as a queue of active parties in arrival order
+	_, err = WaitlistCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("waitlist_queue"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create waitlist index: %w", err)
+	}
+
+	// Floor plans resolve the server covering each section at a given moment
+	_, err = AssignmentCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "section_id", Value: 1}, {Key: "starts_at", Value: 1}, {Key: "ends_at", Value: 1}},
+		Options: options.Index().SetName("section_assignment_shift"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create section assignment index: %w", err)
+	}
+
+	// Transfer history is looked up per order
+	_, err = TransferCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "order_ids", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("table_transfer_order"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create table transfer index: %w", err)
+	}
+
+	// Table numbers are unique per floor area. Temporary combined tables reuse the number of their
+	// primary table; including combined_from (a multikey field, missing on permanent tables) keeps them apart.
+	// Tables without a number yet are left out rather than colliding on null. The index replaces an earlier
+	// one over all tables, which has to be dropped first as the options of an existing index can't change.
+	if _, err := TableCollection.Indexes().DropOne(ctx, "table_number_area"); err != nil && !isIndexNotFound(err) {
+		return fmt.Errorf("failed to drop old table number index: %w", err)
+	}
+	_, err = TableCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "area_id", Value: 1}, {Key: "table_number", Value: 1}, {Key: "combined_from", Value: 1}},
+		Options: options.Index().SetName("table_number_area_numbered").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"table_number": bson.M{"$type": "number"}}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create table number index (duplicate table numbers in an area?): %w", err)
+	}
+
+	// Idempotency keys are unique per user and removed once they expire
+	_, err = IdempotencyCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetName("idempotency_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("idempotency_ttl").SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create idempotency indexes: %w", err)
+	}
+
+	// Station screens list their open tickets oldest first; the order view looks tickets up by order.
+	// An order item goes to each station once: a combo meal's lines share it and may be split across stations,
+	// so it is unique per station rather than per ticket. The older index, unique per item alone, is replaced.
+	if _, err := KitchenTicketCollection.Indexes().DropOne(ctx, "kitchen_ticket_order_item"); err != nil && !isIndexNotFound(err) {
+		return fmt.Errorf("failed to drop old kitchen ticket order item index: %w", err)
+	}
+	_, err = KitchenTicketCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "station_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("kitchen_ticket_station"),
+		},
+		{
+			Keys:    bson.D{{Key: "order_id", Value: 1}},
+			Options: options.Index().SetName("kitchen_ticket_order"),
+		},
+		{
+			Keys:    bson.D{{Key: "items.order_item_id", Value: 1}, {Key: "station_id", Value: 1}},
+			Options: options.Index().SetName("kitchen_ticket_order_item_station").SetUnique(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create kitchen ticket indexes (an order item on several tickets of a station?): %w", err)
+	}
+
+	return nil
+}
+
+// isIndexNotFound reports whether dropping an index failed because it, or its collection, doesn't exist
+func isIndexNotFound(err error) bool {
+	var commandErr mongo.CommandError
+	return errors.As(err, &commandErr) && (commandErr.Code == 27 || commandErr.Code == 26) // IndexNotFound, NamespaceNotFound
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// seedKitchenStations creates the standard kitchen stations so foods can be mapped to them right away
+func seedKitchenStations(ctx context.Context) error {
+	count, err := KitchenStationCollection.CountDocuments(ctx, bson.M{})
+	if err != nil || count > 0 {
+		return err
+	}
+
+	now := time.Now()
+	stations := []interface{}{}
+	for _, name := range []string{"Grill", "Fry", "Bar", "Cold"} {
+		id := primitive.NewObjectID()
+		stations = append(stations, bson.M{
+			"_id":        id,
+			"station_id": id.Hex(),
+			"name":       name,
+			"created_at": now,
+			"updated_at": now,
+		})
+	}
+	_, err = KitchenStationCollection.InsertMany(ctx, stations)
+	return err
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migration is a one-off data change applied once per database
+type Migration struct {
+	ID string
+	Up func(ctx context.Context) error
+}
+
+// Migrations lists every data migration in the order it has to run
+var Migrations = []Migration{
+	{ID: "0001_money_minor_units", Up: migrateMoneyMinorUnits},
+	{ID: "0002_kitchen_stations", Up: seedKitchenStations},
+	{ID: "0003_food_menu_field_names", Up: migrateFoodMenuFieldNames},
+	{ID: "0004_user_field_names", Up: migrateUserFieldNames},
+	{ID: "0005_table_field_names", Up: migrateTableFieldNames},
+	{ID: "0006_order_field_names", Up: migrateOrderFieldNames},
+	{ID: "0007_order_item_unit_prices", Up: migrateOrderItemUnitPrices},
+}
+
+// RunMigrations applies all migrations that are not yet recorded in the migrations collection
+func RunMigrations(ctx context.Context) error {
+	for _, migration := range Migrations {
+		count, err := MigrationCollection.CountDocuments(ctx, bson.M{"migration_id": migration.ID})
+		if err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", migration.ID, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		log.Printf("Applying migration %s", migration.ID)
+		if err := migration.Up(ctx); err != nil {
+			return fmt.Errorf("migration %s failed: %w", migration.ID, err)
+		}
+
+		_, err = MigrationCollection.UpdateOne(ctx,
+			bson.M{"migration_id": migration.ID},
+			bson.M{"$set": bson.M{"migration_id": migration.ID, "applied_at": time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", migration.ID, err)
+		}
+	}
+	return nil
+}
+
+// updateDocuments rewrites every document of a collection for which change reports a modification.
+// Only the returned top-level fields are $set, so concurrent writes to other fields are preserved.
+func updateDocuments(ctx context.Context, collection *mongo.Collection, filter bson.M, change func(doc bson.M) bson.M) error {
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		set := change(doc)
+		if len(set) == 0 {
+			continue
+		}
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc["_id"]}, bson.M{"$set": set}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
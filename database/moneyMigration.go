@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"golang-restaurant-management/money"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrateMoneyMinorUnits converts float prices into {amount, currency} documents in minor units.
+// Order items written before their fields had bson names store the unit price as "unitprice";
+// it is converted under that name and renamed by the order field name migration.
+func migrateMoneyMinorUnits(ctx context.Context) error {
+	targets := []struct {
+		collection *mongo.Collection
+		paths      [][]string
+	}{
+		{FoodCollection, [][]string{{"price"}}},
+		{FoodPriceCollection, [][]string{{"price"}}},
+		{BundleCollection, [][]string{{"price"}, {"slots", "options", "upcharge"}}},
+		{OrderItemCollection, [][]string{{"unit_price"}, {"unitprice"}, {"bundle_selections", "upcharge"}}},
+	}
+
+	for _, target := range targets {
+		if err := convertMoneyFields(ctx, target.collection, bson.M{}, target.paths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateOrderItemUnitPrices converts the float unit prices of order items that still carried the old
+// "unitprice" field name when the money migration ran, and were only renamed to "unit_price" afterwards
+func migrateOrderItemUnitPrices(ctx context.Context) error {
+	return convertMoneyFields(ctx, OrderItemCollection, bson.M{"unit_price": bson.M{"$type": "number"}}, [][]string{{"unit_price"}})
+}
+
+// convertMoneyFields converts the numeric values found along each path of the matching documents into money
+func convertMoneyFields(ctx context.Context, collection *mongo.Collection, filter bson.M, paths [][]string) error {
+	return updateDocuments(ctx, collection, filter, func(doc bson.M) bson.M {
+		set := bson.M{}
+		for _, path := range paths {
+			if value, changed := convertMoneyPath(doc[path[0]], path[1:]); changed {
+				doc[path[0]] = value
+				set[path[0]] = value
+			}
+		}
+		return set
+	})
+}
+
+// convertMoneyPath walks path through documents and arrays and converts the numeric leaf values
+func convertMoneyPath(value interface{}, path []string) (interface{}, bool) {
+	if arr, ok := value.(bson.A); ok {
+		changed := false
+		for i, item := range arr {
+			if converted, c := convertMoneyPath(item, path); c {
+				arr[i] = converted
+				changed = true
+			}
+		}
+		return arr, changed
+	}
+
+	if len(path) > 0 {
+		doc, ok := value.(bson.M)
+		if !ok {
+			return value, false
+		}
+		converted, changed := convertMoneyPath(doc[path[0]], path[1:])
+		if changed {
+			doc[path[0]] = converted
+		}
+		return doc, changed
+	}
+
+	var m money.Money
+	var err error
+	switch v := value.(type) {
+	case float64:
+		m, err = money.FromFloat(v, money.DefaultCurrency(), money.Rounding())
+	case int32:
+		m, err = money.FromFloat(float64(v), money.DefaultCurrency(), money.Rounding())
+	case int64:
+		m, err = money.FromFloat(float64(v), money.DefaultCurrency(), money.Rounding())
+	default:
+		return value, false
+	}
+	if err != nil {
+		return value, false
+	}
+	return m, true
+}
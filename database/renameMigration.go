@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrateFoodMenuFieldNames moves food and menu documents written before their fields had bson names
+// (stored as e.g. "menuid") to the snake_case names the queries use
+func migrateFoodMenuFieldNames(ctx context.Context) error {
+	err := renameFields(ctx, FoodCollection, map[string]string{
+		"foodimage": "food_image",
+		"menuid":    "menu_id",
+		"foodid":    "food_id",
+		"createdat": "created_at",
+		"updatedat": "updated_at",
+	})
+	if err != nil {
+		return err
+	}
+	return renameFields(ctx, MenuCollection, map[string]string{
+		"menuid":    "menu_id",
+		"startdate": "start_date",
+		"enddate":   "end_date",
+		"createdat": "created_at",
+		"updatedat": "updated_at",
+	})
+}
+
+// migrateUserFieldNames moves user documents written before their fields had bson names to the snake_case names
+func migrateUserFieldNames(ctx context.Context) error {
+	return renameFields(ctx, UserCollection, map[string]string{
+		"userid":       "user_id",
+		"firstname":    "first_name",
+		"lastname":     "last_name",
+		"refreshtoken": "refresh_token",
+		"createdat":    "created_at",
+		"updatedat":    "updated_at",
+	})
+}
+
+// migrateTableFieldNames moves table documents written before their fields had bson names to the snake_case names
+func migrateTableFieldNames(ctx context.Context) error {
+	return renameFields(ctx, TableCollection, map[string]string{
+		"numberofguests": "number_of_guests",
+		"tablenumber":    "table_number",
+		"tableid":        "table_id",
+		"createdat":      "created_at",
+		"updatedat":      "updated_at",
+	})
+}
+
+// migrateOrderFieldNames moves order, order item and invoice documents written before their fields had bson names
+// to the snake_case names the queries use
+func migrateOrderFieldNames(ctx context.Context) error {
+	err := renameFields(ctx, OrderCollection, map[string]string{
+		"orderid":   "order_id",
+		"tableid":   "table_id",
+		"orderdate": "order_date",
+		"createdat": "created_at",
+		"updatedat": "updated_at",
+	})
+	if err != nil {
+		return err
+	}
+	err = renameFields(ctx, OrderItemCollection, map[string]string{
+		"unitprice":   "unit_price",
+		"orderid":     "order_id",
+		"orderitemid": "order_item_id",
+		"foodid":      "food_id",
+		"createdat":   "created_at",
+		"updatedat":   "updated_at",
+	})
+	if err != nil {
+		return err
+	}
+	return renameFields(ctx, InvoiceCollection, map[string]string{
+		"invoiceid":      "invoice_id",
+		"orderid":        "order_id",
+		"paymentmethod":  "payment_method",
+		"paymentstatus":  "payment_status",
+		"paymentduedate": "payment_due_date",
+		"createdat":      "created_at",
+		"updatedat":      "updated_at",
+	})
+}
+
+// renameFields renames top-level fields from their old to their new name. Documents that already
+// carry the new field were written since the rename, so only their stale old field is dropped.
+func renameFields(ctx context.Context, collection *mongo.Collection, renames map[string]string) error {
+	for from, to := range renames {
+		_, err := collection.UpdateMany(ctx,
+			bson.M{from: bson.M{"$exists": true}, to: bson.M{"$exists": false}},
+			bson.M{"$rename": bson.M{from: to}},
+		)
+		if err != nil {
+			return err
+		}
+		_, err = collection.UpdateMany(ctx,
+			bson.M{from: bson.M{"$exists": true}},
+			bson.M{"$unset": bson.M{from: ""}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"golang-restaurant-management/models"
+	"golang-restaurant-management/money"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RestaurantSettingsID identifies the settings document of this restaurant
+const RestaurantSettingsID = "restaurant"
+
+var (
+	tableQRKeyMu sync.RWMutex
+	tableQRKey   []byte
+)
+
+// TableQRKey returns the key signing the links encoded in table QR codes
+func TableQRKey() []byte {
+	tableQRKeyMu.RLock()
+	defer tableQRKeyMu.RUnlock()
+	return tableQRKey
+}
+
+// setTableQRKey replaces the key used for new and verified links
+func setTableQRKey(key string) {
+	tableQRKeyMu.Lock()
+	defer tableQRKeyMu.Unlock()
+	tableQRKey = []byte(key)
+}
+
+// LoadSettings reads the restaurant settings (creating them from CURRENCY/ROUNDING_MODE on first start)
+// and applies them to the money package
+func LoadSettings(ctx context.Context) (models.Settings, error) {
+	var settings models.Settings
+	err := SettingsCollection.FindOne(ctx, bson.M{"settings_id": RestaurantSettingsID}).Decode(&settings)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		settings = models.Settings{
+			ID:           primitive.NewObjectID(),
+			SettingsID:   RestaurantSettingsID,
+			Currency:     strings.ToUpper(os.Getenv("CURRENCY")),
+			RoundingMode: os.Getenv("ROUNDING_MODE"),
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+		if settings.Currency == "" {
+			settings.Currency = money.DefaultCurrency()
+		}
+		if settings.RoundingMode == "" {
+			settings.RoundingMode = string(money.Rounding())
+		}
+		if _, err = SettingsCollection.InsertOne(ctx, settings); err != nil {
+			return settings, fmt.Errorf("failed to create settings: %w", err)
+		}
+	} else if err != nil {
+		return settings, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	// Deployments that predate table QR codes get their signing key on first start
+	if settings.TableQRKey == "" {
+		if settings.TableQRKey, err = storeTableQRKey(ctx); err != nil {
+			return settings, err
+		}
+	}
+
+	if err := ApplySettings(settings); err != nil {
+		return settings, err
+	}
+	return settings, nil
+}
+
+// RotateTableQRKey replaces the table QR signing key, invalidating every printed QR code
+func RotateTableQRKey(ctx context.Context) error {
+	key, err := storeTableQRKey(ctx)
+	if err != nil {
+		return err
+	}
+	setTableQRKey(key)
+	return nil
+}
+
+// storeTableQRKey generates a random signing key and saves it in the settings
+func storeTableQRKey(ctx context.Context) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate table QR key: %w", err)
+	}
+	key := hex.EncodeToString(raw)
+
+	update := bson.M{"$set": bson.M{"table_qr_key": key, "updated_at": time.Now()}}
+	if _, err := SettingsCollection.UpdateOne(ctx, bson.M{"settings_id": RestaurantSettingsID}, update); err != nil {
+		return "", fmt.Errorf("failed to store table QR key: %w", err)
+	}
+	return key, nil
+}
+
+// ApplySettings makes the settings effective for all money parsing and rounding
+func ApplySettings(settings models.Settings) error {
+	if !money.ValidCurrency(settings.Currency) {
+		return fmt.Errorf("invalid currency %q", settings.Currency)
+	}
+	if !money.ValidRoundingMode(money.RoundingMode(settings.RoundingMode)) {
+		return fmt.Errorf("invalid rounding mode %q", settings.RoundingMode)
+	}
+	money.SetDefaults(settings.Currency, money.RoundingMode(settings.RoundingMode))
+	if settings.TableQRKey != "" {
+		setTableQRKey(settings.TableQRKey)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package events
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// subscriptionBuffer is how many events may wait for a slow subscriber before it is dropped
+const subscriptionBuffer = 64
+
+// Bus fans published events out to subscribers and keeps the most recent ones so a subscriber
+// that reconnects can resume from the last event it saw. It lives in memory: a restart starts a new
+// epoch, and subscribers resuming from an older one are told to reload instead.
+type Bus struct {
+	size  int
+	epoch string
+
+	mu          sync.Mutex
+	seq         uint64
+	history     []Event
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the events matching its filter on C until it is closed.
+// C is also closed when the subscriber falls too far behind; it can then resubscribe from its last event.
+type Subscription struct {
+	C <-chan Event
+
+	ch     chan Event
+	filter Filter
+	bus    *Bus
+}
+
+// NewBus creates a bus that keeps the last size events for resuming subscribers
+func NewBus(size int) *Bus {
+	return &Bus{
+		size:        size,
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Publish assigns the event its ID and time and delivers it to every matching subscriber.
+// It never blocks: subscribers whose buffer is full are dropped.
+func (b *Bus) Publish(event Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event.ID = b.epoch + "-" + strconv.FormatUint(b.seq, 10)
+	event.Resource = resource(event.Type)
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > 2*b.size {
+		b.history = append([]Event(nil), b.history[len(b.history)-b.size:]...)
+	}
+
+	for sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			b.drop(sub)
+		}
+	}
+	return event
+}
+
+// Subscribe registers a subscriber. With a lastEventID it also returns the matching events published since
+// that event; resumed is false when those are no longer known (the bus restarted or they were trimmed).
+func (b *Bus) Subscribe(filter Filter, lastEventID string) (sub *Subscription, missed []Event, resumed bool) {
+	ch := make(chan Event, subscriptionBuffer)
+	sub = &Subscription{C: ch, ch: ch, filter: filter, bus: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	resumed = true
+	if lastEventID != "" {
+		missed, resumed = b.since(lastEventID, filter)
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub, missed, resumed
+}
+
+// Close unregisters the subscription and closes its channel
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.drop(s)
+}
+
+// since returns the kept events after the given one that match the filter
+func (b *Bus) since(lastEventID string, filter Filter) ([]Event, bool) {
+	epoch, seq, ok := parseID(lastEventID)
+	if !ok || epoch != b.epoch || seq > b.seq {
+		return nil, false
+	}
+	if seq == b.seq {
+		return nil, true
+	}
+
+	// Sequence numbers are contiguous, so the position of an event in the history follows from its number
+	oldest := b.seq - uint64(len(b.history)) + 1
+	if seq+1 < oldest {
+		return nil, false
+	}
+	missed := []Event{}
+	for _, event := range b.history[seq+1-oldest:] {
+		if filter == nil || filter(event) {
+			missed = append(missed, event)
+		}
+	}
+	return missed, true
+}
+
+// drop removes a subscriber; the caller holds the lock
+func (b *Bus) drop(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.ch)
+}
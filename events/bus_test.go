@@ -0,0 +1,74 @@
+package events
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestBusResume(t *testing.T) {
+	bus := NewBus(3)
+	var published []Event
+	for i := 0; i < 4; i++ {
+		published = append(published, bus.Publish(Event{Type: OrderCreated, ResourceID: strconv.Itoa(i)}))
+	}
+
+	sub, missed, resumed := bus.Subscribe(nil, published[1].ID)
+	defer sub.Close()
+	if !resumed || len(missed) != 2 || missed[0].ID != published[2].ID || missed[1].ID != published[3].ID {
+		t.Errorf("resume after the second event: %v %v, want the last two events", missed, resumed)
+	}
+
+	sub, missed, resumed = bus.Subscribe(nil, published[3].ID)
+	defer sub.Close()
+	if !resumed || len(missed) != 0 {
+		t.Errorf("resume after the latest event: %v %v, want nothing missed", missed, resumed)
+	}
+}
+
+func TestBusResumeFilters(t *testing.T) {
+	bus := NewBus(10)
+	first := bus.Publish(Event{Type: OrderCreated})
+	bus.Publish(Event{Type: TableStatusChanged})
+	order := bus.Publish(Event{Type: OrderStatusChanged})
+
+	onlyOrders := func(event Event) bool { return event.Resource == "order" }
+	sub, missed, resumed := bus.Subscribe(onlyOrders, first.ID)
+	defer sub.Close()
+	if !resumed || len(missed) != 1 || missed[0].ID != order.ID {
+		t.Errorf("filtered resume: %v %v, want only the order event", missed, resumed)
+	}
+}
+
+func TestBusResumeUnknown(t *testing.T) {
+	bus := NewBus(2)
+	var published []Event
+	for i := 0; i < 6; i++ {
+		published = append(published, bus.Publish(Event{Type: OrderCreated}))
+	}
+
+	tests := map[string]string{
+		"trimmed from the history": published[0].ID,
+		"from another epoch":       "otherepoch-1",
+		"from the future":          bus.epoch + "-99",
+		"malformed":                "not-an-id",
+	}
+	for name, id := range tests {
+		sub, missed, resumed := bus.Subscribe(nil, id)
+		sub.Close()
+		if resumed || len(missed) != 0 {
+			t.Errorf("%s: %v %v, want a reset", name, missed, resumed)
+		}
+	}
+}
+
+func TestBusDeliversLiveEvents(t *testing.T) {
+	bus := NewBus(10)
+	sub, _, _ := bus.Subscribe(func(event Event) bool { return event.Resource == "table" }, "")
+	defer sub.Close()
+
+	bus.Publish(Event{Type: OrderCreated})
+	table := bus.Publish(Event{Type: TableUpdated})
+	if got := <-sub.C; got.ID != table.ID {
+		t.Errorf("received %v, want the table event", got)
+	}
+}
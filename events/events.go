@@ -0,0 +1,77 @@
+package events
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event types published by the controllers after successful writes
+const (
+	OrderCreated       = "order.created"
+	OrderUpdated       = "order.updated"
+	OrderStatusChanged = "order.status_changed"
+
+	OrderItemCreated = "order_item.created"
+	OrderItemUpdated = "order_item.updated"
+
+	TableCreated       = "table.created"
+	TableUpdated       = "table.updated"
+	TableStatusChanged = "table.status_changed"
+	TableDeleted       = "table.deleted"
+
+	InvoiceCreated = "invoice.created"
+	InvoiceUpdated = "invoice.updated"
+
+	KitchenTicketCreated = "kitchen_ticket.created"
+	KitchenTicketUpdated = "kitchen_ticket.updated"
+
+	// StreamReset tells a resuming subscriber that events were missed and it has to reload its state
+	StreamReset = "stream.reset"
+)
+
+// Resources events are about, the part of the event type before the dot
+var Resources = []string{"order", "order_item", "table", "invoice", "kitchen_ticket"}
+
+// Event is a change to a resource, pushed to the subscribers of the stream endpoints
+type Event struct {
+	ID         string      `json:"id"`                    // Position in the stream, used to resume after a reconnect
+	Type       string      `json:"type"`                  // What happened (e.g. "order.status_changed")
+	Resource   string      `json:"resource"`              // Kind of resource that changed (e.g. "order")
+	ResourceID string      `json:"resource_id"`           // ID of the resource that changed
+	OrderID    string      `json:"order_id,omitempty"`    // Order the change belongs to, if any
+	TableID    string      `json:"table_id,omitempty"`    // Table the change belongs to, if any
+	StationIDs []string    `json:"station_ids,omitempty"` // Kitchen stations the change concerns, if any
+	Data       interface{} `json:"data,omitempty"`        // The resource as written
+	At         time.Time   `json:"at"`                    // When the event was published
+}
+
+// Filter decides whether a subscriber receives an event
+type Filter func(Event) bool
+
+// Default is the bus the controllers publish to
+var Default = NewBus(1000)
+
+// Publish publishes an event on the default bus
+func Publish(event Event) Event {
+	return Default.Publish(event)
+}
+
+// resource returns the part of an event type before the dot
+func resource(eventType string) string {
+	name, _, _ := strings.Cut(eventType, ".")
+	return name
+}
+
+// parseID splits an event ID into the bus epoch and sequence number
+func parseID(id string) (string, uint64, bool) {
+	i := strings.LastIndex(id, "-")
+	if i < 0 {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseUint(id[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return id[:i], seq, true
+}
@@ -0,0 +1,111 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	MaxImageSize      = 5 << 20 // Maximum accepted upload size (5 MiB)
+	MaxImagePixels    = 40e6    // Guards against decompression bombs
+	ThumbnailMaxWidth = 320     // Bounding box thumbnails are scaled into
+)
+
+// ErrUnsupportedImage is returned when the uploaded bytes are not an accepted image type
+var ErrUnsupportedImage = errors.New("unsupported image type, expected jpeg, png or gif")
+
+// Extensions for the image types we accept, keyed by sniffed content type
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ProcessedImage holds an uploaded image and its generated thumbnail
+type ProcessedImage struct {
+	ContentType string
+	Extension   string
+	Original    []byte
+	Thumbnail   []byte
+	Width       int
+	Height      int
+}
+
+// ProcessImage sniffs the real content type of data, validates its dimensions and renders a thumbnail.
+// The client-supplied Content-Type header is never trusted.
+func ProcessImage(data []byte) (*ProcessedImage, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	if config.Width*config.Height > MaxImagePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d are too large", config.Width, config.Height)
+	}
+
+	var src image.Image
+	switch contentType {
+	case "image/jpeg":
+		src, err = jpeg.Decode(bytes.NewReader(data))
+	case "image/png":
+		src, err = png.Decode(bytes.NewReader(data))
+	case "image/gif":
+		src, err = gif.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	thumb, err := Thumbnail(src, ThumbnailMaxWidth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProcessedImage{
+		ContentType: contentType,
+		Extension:   ext,
+		Original:    data,
+		Thumbnail:   thumb,
+		Width:       config.Width,
+		Height:      config.Height,
+	}, nil
+}
+
+// Thumbnail scales src down to fit a maxSize x maxSize box and encodes it as JPEG
+func Thumbnail(src image.Image, maxSize int) ([]byte, error) {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			height = max(1, height*maxSize/width)
+			width = maxSize
+		} else {
+			width = max(1, width*maxSize/height)
+			height = maxSize
+		}
+	}
+
+	// Paint on white so transparent PNGs don't turn black in JPEG
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
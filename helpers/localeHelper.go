@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the language the base name/description fields are written in
+var DefaultLocale = "en"
+
+// SupportedLocales lists the locales menus can be translated into
+var SupportedLocales = []string{"en", "es", "ja"}
+
+func init() {
+	if v := os.Getenv("DEFAULT_LOCALE"); v != "" {
+		DefaultLocale = NormalizeLocale(v)
+	}
+	if v := os.Getenv("SUPPORTED_LOCALES"); v != "" {
+		SupportedLocales = nil
+		for _, locale := range strings.Split(v, ",") {
+			if locale = NormalizeLocale(locale); locale != "" {
+				SupportedLocales = append(SupportedLocales, locale)
+			}
+		}
+	}
+	if !IsSupportedLocale(DefaultLocale) {
+		SupportedLocales = append([]string{DefaultLocale}, SupportedLocales...)
+	}
+}
+
+// NormalizeLocale lower-cases a language tag and uses "-" as separator (es_MX -> es-mx)
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// IsSupportedLocale reports whether locale is one of SupportedLocales
+func IsSupportedLocale(locale string) bool {
+	for _, supported := range SupportedLocales {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// NegotiateLocale picks the best supported locale from an explicit ?lang= value or an Accept-Language header.
+// Regional variants fall back to their base language (es-MX -> es) and everything else to DefaultLocale.
+func NegotiateLocale(lang, acceptLanguage string) string {
+	if locale := matchLocale(lang); locale != "" {
+		return locale
+	}
+
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+
+	for _, c := range candidates {
+		if locale := matchLocale(c.tag); locale != "" {
+			return locale
+		}
+	}
+
+	return DefaultLocale
+}
+
+// matchLocale maps a requested tag onto a supported locale, or "" if there is none
+func matchLocale(tag string) string {
+	tag = NormalizeLocale(tag)
+	if tag == "" || tag == "*" {
+		return ""
+	}
+	if IsSupportedLocale(tag) {
+		return tag
+	}
+	if base, _, found := strings.Cut(tag, "-"); found && IsSupportedLocale(base) {
+		return base
+	}
+	return ""
+}
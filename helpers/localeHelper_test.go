@@ -0,0 +1,34 @@
+package helpers
+
+import "testing"
+
+func TestNegotiateLocale(t *testing.T) {
+	defer func(locales []string, fallback string) {
+		SupportedLocales, DefaultLocale = locales, fallback
+	}(SupportedLocales, DefaultLocale)
+	SupportedLocales, DefaultLocale = []string{"en", "es", "ja"}, "en"
+
+	tests := []struct {
+		name           string
+		lang           string
+		acceptLanguage string
+		want           string
+	}{
+		{"nothing requested", "", "", "en"},
+		{"explicit lang", "ja", "es", "ja"},
+		{"explicit lang is normalized", "ES_mx", "", "es"},
+		{"unsupported lang falls back to header", "fr", "es", "es"},
+		{"header order", "", "ja,es", "ja"},
+		{"header quality", "", "es;q=0.5, ja;q=0.8", "ja"},
+		{"regional variant", "", "es-MX", "es"},
+		{"unsupported skipped", "", "fr-FR, de;q=0.9, es;q=0.1", "es"},
+		{"zero quality ignored", "", "ja;q=0, es;q=0.2", "es"},
+		{"wildcard", "", "*", "en"},
+		{"nothing supported", "", "fr, de", "en"},
+	}
+	for _, tt := range tests {
+		if got := NegotiateLocale(tt.lang, tt.acceptLanguage); got != tt.want {
+			t.Errorf("%s: NegotiateLocale(%q, %q) = %q, want %q", tt.name, tt.lang, tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
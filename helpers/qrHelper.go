@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	DefaultQRSize = 256  // Default edge length of rendered QR codes in pixels
+	MinQRSize     = 128  // Smallest QR code that still scans reliably from a sticker
+	MaxQRSize     = 1024 // Largest QR code we render
+)
+
+// SignTableLink signs a table ID and its QR version; bumping either the key or the version invalidates the link
+func SignTableLink(key []byte, tableID string, version int) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(tableID + "." + strconv.Itoa(version)))
+	// 128 bits are plenty and keep the encoded URL (and so the QR code) small
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// VerifyTableLink checks a signature produced by SignTableLink in constant time
+func VerifyTableLink(key []byte, tableID string, version int, signature string) bool {
+	expected := SignTableLink(key, tableID, version)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// QRCodePNG renders content as a PNG QR code of the given edge length
+func QRCodePNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+// QRCodeSVG renders content as a scalable SVG QR code of the given edge length
+func QRCodeSVG(content string, size int) ([]byte, error) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := code.Bitmap()
+	modules := len(bitmap)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, modules, modules)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			// Merge horizontal runs of dark modules into one rectangle
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes(), nil
+}
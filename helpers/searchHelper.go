@@ -0,0 +1,94 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SearchTerms splits a free-text query into lower-cased words
+func SearchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// MaxTypos returns how many edits a search term may be off by
+func MaxTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Levenshtein returns the edit distance between two strings
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// FuzzyScore scores how well the query terms match the given text.
+// An exact word scores 1, a word prefix 0.75 and a near miss within MaxTypos 0.5.
+// The result is the average over all terms, so 0 means no term matched at all.
+func FuzzyScore(terms []string, text string) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	words := SearchTerms(text)
+	total := 0.0
+	for _, term := range terms {
+		best := 0.0
+		for _, word := range words {
+			switch {
+			case word == term:
+				best = 1
+			case strings.HasPrefix(word, term):
+				best = max(best, 0.75)
+			case MaxTypos(term) > 0:
+				if PrefixDistance(term, word) <= MaxTypos(term) {
+					best = max(best, 0.5)
+				}
+			}
+			if best == 1 {
+				break
+			}
+		}
+		total += best
+	}
+
+	return total / float64(len(terms))
+}
+
+// PrefixDistance returns the smallest edit distance between the term and the word or any of its prefixes
+// within MaxTypos of the term's length, so "capucino" and "cappucino" both still find "cappuccino"
+func PrefixDistance(term, word string) int {
+	rt, rw := []rune(term), []rune(word)
+	typos := MaxTypos(term)
+	best := Levenshtein(term, word)
+	for n := max(len(rt)-typos, 1); n <= min(len(rt)+typos, len(rw)); n++ {
+		best = min(best, Levenshtein(term, string(rw[:n])))
+	}
+	return best
+}
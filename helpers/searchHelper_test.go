@@ -0,0 +1,50 @@
+package helpers
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"pizza", "pizza", 0},
+		{"", "soup", 4},
+		{"pizza", "piza", 1},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query string
+		text  string
+		want  float64
+	}{
+		{"pizza", "Margherita Pizza", 1},
+		{"marg", "Margherita Pizza", 0.75},
+		{"pizzza", "Margherita Pizza", 0.5},
+		{"capu", "Cappuccino", 0.5},
+		{"capp", "Cappuccino", 0.75},
+		{"capucino", "Cappuccino", 0.5},
+		{"cappucino", "Cappuccino", 0.5},
+		{"xappuccino", "Cappuccino", 0.5},
+		{"cappuccimo", "Cappuccino", 0.5},
+		{"teri", "Teriyaki", 0.75},
+		{"tae", "Tea", 0},
+		{"burger", "Margherita Pizza", 0},
+		{"pizza burger", "Margherita Pizza", 0.5},
+		{"", "Margherita Pizza", 0},
+	}
+	for _, tt := range tests {
+		if got := FuzzyScore(SearchTerms(tt.query), tt.text); got != tt.want {
+			t.Errorf("FuzzyScore(%q, %q) = %v, want %v", tt.query, tt.text, got, tt.want)
+		}
+	}
+}
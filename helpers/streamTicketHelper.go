@@ -0,0 +1,64 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// StreamTicketTTL is how long a stream ticket can be redeemed after it was issued
+const StreamTicketTTL = 30 * time.Second
+
+// Browsers can't set headers on EventSource and WebSocket connections. Instead of putting the JWT in the
+// URL, where it ends up in access logs and browser history, clients exchange it for a stream ticket:
+// a random value that opens one stream within StreamTicketTTL and is useless afterwards.
+var (
+	streamTicketsMu sync.Mutex
+	streamTickets   = map[string]streamTicket{}
+)
+
+// streamTicket is the identity a ticket stands for until it expires
+type streamTicket struct {
+	claims    SignedDetails
+	expiresAt time.Time
+}
+
+// IssueStreamTicket returns a single-use ticket standing for the given identity and when it expires
+func IssueStreamTicket(claims SignedDetails) (string, time.Time, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	ticket := hex.EncodeToString(raw)
+	now := time.Now()
+	expiresAt := now.Add(StreamTicketTTL)
+
+	streamTicketsMu.Lock()
+	defer streamTicketsMu.Unlock()
+
+	// Tickets that were never redeemed are dropped on the next issue
+	for key, issued := range streamTickets {
+		if !now.Before(issued.expiresAt) {
+			delete(streamTickets, key)
+		}
+	}
+	streamTickets[ticket] = streamTicket{claims: claims, expiresAt: expiresAt}
+	return ticket, expiresAt, nil
+}
+
+// RedeemStreamTicket returns the identity of a ticket and invalidates it. It fails for unknown, used and expired tickets.
+func RedeemStreamTicket(ticket string) (*SignedDetails, bool) {
+	streamTicketsMu.Lock()
+	defer streamTicketsMu.Unlock()
+
+	issued, ok := streamTickets[ticket]
+	if !ok {
+		return nil, false
+	}
+	delete(streamTickets, ticket)
+	if !time.Now().Before(issued.expiresAt) {
+		return nil, false
+	}
+	return &issued.claims, true
+}
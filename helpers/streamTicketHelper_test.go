@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamTicket(t *testing.T) {
+	ticket, expiresAt, err := IssueStreamTicket(SignedDetails{Uid: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expiresAt.After(time.Now().Add(StreamTicketTTL)) {
+		t.Errorf("expires at %v, later than the TTL", expiresAt)
+	}
+
+	claims, ok := RedeemStreamTicket(ticket)
+	if !ok || claims.Uid != "user-1" {
+		t.Fatalf("redeem: %v %v, want user-1", claims, ok)
+	}
+	if _, ok := RedeemStreamTicket(ticket); ok {
+		t.Error("a ticket should only open one stream")
+	}
+	if _, ok := RedeemStreamTicket("unknown"); ok {
+		t.Error("unknown ticket accepted")
+	}
+}
+
+func TestStreamTicketExpires(t *testing.T) {
+	ticket, _, err := IssueStreamTicket(SignedDetails{Uid: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	streamTicketsMu.Lock()
+	issued := streamTickets[ticket]
+	issued.expiresAt = time.Now().Add(-time.Second)
+	streamTickets[ticket] = issued
+	streamTicketsMu.Unlock()
+
+	if _, ok := RedeemStreamTicket(ticket); ok {
+		t.Error("expired ticket accepted")
+	}
+}
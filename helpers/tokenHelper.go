@@ -5,14 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"golang-restaurant-management/database"
-	"log"
 	"os"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 	"go.mongodb.org/mongo-driver/bson"
 	// "go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -24,20 +22,30 @@ type SignedDetails struct {
 	jwt.StandardClaims
 }
 
-var userCollection *mongo.Collection = database.OpenCollection(database.Client, "user")
-
-// Ensure SECRET_KEY is set
+// SECRET_KEY signs and verifies JWT tokens
 var SECRET_KEY string
 
+// ErrMissingSecretKey is returned when tokens are signed or verified without a SECRET_KEY
+var ErrMissingSecretKey = errors.New("SECRET_KEY is not set in environment variables")
+
 func init() {
 	SECRET_KEY = os.Getenv("SECRET_KEY")
+}
+
+// CheckSecretKey fails with ErrMissingSecretKey when no key is configured, so servers can refuse to start without one
+func CheckSecretKey() error {
 	if SECRET_KEY == "" {
-		log.Fatal("SECRET_KEY is not set in environment variables")
+		return ErrMissingSecretKey
 	}
+	return nil
 }
 
 // Generate JWT Tokens (Access & Refresh)
 func GenerateAllTokens(email, firstName, lastName, uid string) (string, string, error) {
+	if err := CheckSecretKey(); err != nil {
+		return "", "", err
+	}
+
 	accessTokenClaims := &SignedDetails{
 		Email:     email,
 		FirstName: firstName,
@@ -81,7 +89,7 @@ func UpdateAllTokens(signedToken, signedRefreshToken, userId string) error {
 	filter := bson.M{"user_id": userId}
 	opt := options.Update().SetUpsert(true)
 
-	_, err := userCollection.UpdateOne(ctx, filter, bson.D{{"$set", updateObj}}, opt)
+	_, err := database.UserCollection.UpdateOne(ctx, filter, bson.D{{"$set", updateObj}}, opt)
 	if err != nil {
 		return fmt.Errorf("failed to update tokens: %w", err)
 	}
@@ -90,6 +98,10 @@ func UpdateAllTokens(signedToken, signedRefreshToken, userId string) error {
 
 // Validate JWT Token
 func ValidateToken(signedToken string) (*SignedDetails, error) {
+	if err := CheckSecretKey(); err != nil {
+		return nil, err
+	}
+
 	token, err := jwt.ParseWithClaims(signedToken, &SignedDetails{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(SECRET_KEY), nil
 	})
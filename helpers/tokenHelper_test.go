@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTokensNeedSecretKey(t *testing.T) {
+	defer func(key string) { SECRET_KEY = key }(SECRET_KEY)
+
+	SECRET_KEY = ""
+	if _, _, err := GenerateAllTokens("a@example.com", "A", "B", "user-1"); !errors.Is(err, ErrMissingSecretKey) {
+		t.Errorf("signing without a key: %v, want ErrMissingSecretKey", err)
+	}
+	if _, err := ValidateToken("token"); !errors.Is(err, ErrMissingSecretKey) {
+		t.Errorf("validating without a key: %v, want ErrMissingSecretKey", err)
+	}
+
+	SECRET_KEY = "secret"
+	token, _, err := GenerateAllTokens("a@example.com", "A", "B", "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ValidateToken(token)
+	if err != nil || claims.Uid != "user-1" {
+		t.Errorf("round trip: %v %v, want user-1", claims, err)
+	}
+}
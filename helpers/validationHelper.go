@@ -1,6 +1,22 @@
 package helpers
 
-import "github.com/go-playground/validator/v10"
+import (
+	"golang-restaurant-management/money"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+)
 
 // Global Validator Instance
-var Validate = validator.New()
+var Validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// Money fields are validated on their minor-unit amount, so tags like gt=0 keep working
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(money.Money).Amount
+	}, money.Money{})
+
+	return v
+}
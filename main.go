@@ -6,12 +6,19 @@ import (
     "log"
     "os"
     "os/signal"
+    "strconv"
+    "strings"
     "syscall"
     "time"
 
+    "golang-restaurant-management/cache"
+    "golang-restaurant-management/controllers"
     "golang-restaurant-management/database"
+    "golang-restaurant-management/helpers"
     "golang-restaurant-management/middleware"
+    "golang-restaurant-management/notify"
     "golang-restaurant-management/routes"
+    "golang-restaurant-management/storage"
 
     "github.com/gin-gonic/gin"
 )
@@ -22,6 +29,10 @@ func main() {
         port = "8000"
     }
 
+    if err := helpers.CheckSecretKey(); err != nil {
+        log.Fatal(err)
+    }
+
     client, err := database.DbInstance()
     if err != nil {
         log.Fatalf("Failed to connect to MongoDB: %v", err)
@@ -32,15 +43,91 @@ func main() {
 
     database.InitCollections(client)
 
+    setupCtx, setupCancel := context.WithTimeout(context.Background(), 5*time.Minute)
+    if _, err := database.LoadSettings(setupCtx); err != nil {
+        log.Fatalf("Failed to load restaurant settings: %v", err)
+    }
+    // Migrations run first so unique indexes are built on the migrated field names
+    if err := database.RunMigrations(setupCtx); err != nil {
+        log.Fatalf("Failed to migrate MongoDB data: %v", err)
+    }
+    if err := database.CreateIndexes(setupCtx); err != nil {
+        log.Fatalf("Failed to create MongoDB indexes: %v", err)
+    }
+    setupCancel()
+
+    uploadDir := os.Getenv("UPLOAD_DIR")
+    if uploadDir == "" {
+        uploadDir = "uploads"
+    }
+    localStorage, err := storage.NewLocalStorage(uploadDir, "/uploads")
+    if err != nil {
+        log.Fatalf("Failed to initialise file storage: %v", err)
+    }
+    storage.Default = localStorage
+
+    if webhook := os.Getenv("NOTIFY_WEBHOOK_URL"); webhook != "" {
+        notify.Default = notify.NewWebhookNotifier(webhook)
+    }
+
+    if ttl := os.Getenv("PUBLIC_MENU_CACHE_TTL"); ttl != "" {
+        duration, err := time.ParseDuration(ttl)
+        if err != nil {
+            log.Fatalf("Invalid PUBLIC_MENU_CACHE_TTL: %v", err)
+        }
+        cache.PublicMenu.TTL = duration
+    }
+
+    if ttl := os.Getenv("IDEMPOTENCY_TTL"); ttl != "" {
+        duration, err := time.ParseDuration(ttl)
+        if err != nil {
+            log.Fatalf("Invalid IDEMPOTENCY_TTL: %v", err)
+        }
+        middleware.IdempotencyTTL = duration
+    }
+    if size := os.Getenv("IDEMPOTENCY_MAX_BODY"); size != "" {
+        limit, err := strconv.ParseInt(size, 10, 64)
+        if err != nil || limit < 1 {
+            log.Fatalf("Invalid IDEMPOTENCY_MAX_BODY: %q", size)
+        }
+        middleware.IdempotencyMaxBody = limit
+    }
+
+    if origins := os.Getenv("EVENT_ALLOWED_ORIGINS"); origins != "" {
+        for _, origin := range strings.Split(origins, ",") {
+            if origin = strings.TrimSpace(origin); origin != "" {
+                controllers.EventAllowedOrigins = append(controllers.EventAllowedOrigins, origin)
+            }
+        }
+    }
+
+    // Scheduled prices are copied onto their foods once they take effect
+    go controllers.RunPriceScheduler(context.Background(), time.Minute)
+
     router := gin.Default()
+    router.MaxMultipartMemory = 8 << 20
+    routes.UploadRoutes(router)
     routes.UserRoutes(router)
+    routes.PublicRoutes(router)
     router.Use(middleware.Authentication())
+    router.Use(middleware.Idempotency())
     routes.FoodRoutes(router)
     routes.MenuRoutes(router)
     routes.TableRoutes(router)
     routes.OrderRoutes(router)
     routes.OrderItemRoutes(router)
     routes.InvoiceRoutes(router)
+    routes.CategoryRoutes(router)
+    routes.BundleRoutes(router)
+    routes.TranslationRoutes(router)
+    routes.SettingsRoutes(router)
+    routes.SearchRoutes(router)
+    routes.PricingRuleRoutes(router)
+    routes.ReservationRoutes(router)
+    routes.WaitlistRoutes(router)
+    routes.FloorRoutes(router)
+    routes.KitchenRoutes(router)
+    routes.EventRoutes(router)
 
     go func() {
         fmt.Println("Server running on port:", port)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"golang-restaurant-management/helpers"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,16 +13,27 @@ import (
 func Authentication() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		clientToken := c.Request.Header.Get("token")
-		if clientToken == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No Authorization token provided"})
-			return
-		}
+		var claims *helpers.SignedDetails
+		if clientToken == "" && isStreamRequest(c) && c.Query("ticket") != "" {
+			// Browsers can't set headers on EventSource and WebSocket connections, so streams pass a stream ticket instead
+			var ok bool
+			if claims, ok = helpers.RedeemStreamTicket(c.Query("ticket")); !ok {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired stream ticket"})
+				return
+			}
+		} else {
+			if clientToken == "" {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No Authorization token provided"})
+				return
+			}
 
-		// Validate Token
-		claims, err := helpers.ValidateToken(clientToken)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Invalid token: %v", err)})
-			return
+			// Validate Token
+			var err error
+			claims, err = helpers.ValidateToken(clientToken)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Invalid token: %v", err)})
+				return
+			}
 		}
 
 		// Store Claims in Context
@@ -34,3 +46,9 @@ func Authentication() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// isStreamRequest reports whether the request opens an event stream or a WebSocket
+func isStreamRequest(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), "text/event-stream") ||
+		strings.EqualFold(c.GetHeader("Upgrade"), "websocket")
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"golang-restaurant-management/cache"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvalidateCache Middleware drops every entry of the store after a successful write request,
+// so cached read-only views never outlive the data they were rendered from.
+func InvalidateCache(store *cache.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		if c.Writer.Status() < http.StatusBadRequest {
+			store.Invalidate()
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/models"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IdempotencyHeader is the request header clients set to make a POST safe to retry
+const IdempotencyHeader = "Idempotency-Key"
+
+// IdempotencyTTL is how long a key and its stored response are kept
+var IdempotencyTTL = 24 * time.Hour
+
+// IdempotencyMaxBody is the largest request body, in bytes, a request carrying an Idempotency-Key may send.
+// The body is read into memory to hash it, so larger requests are rejected instead.
+var IdempotencyMaxBody int64 = 10 << 20
+
+// idempotencyLockTimeout is how long a request may hold its key before a retry may take over,
+// a bit longer than the handlers' own timeout
+const idempotencyLockTimeout = 2 * time.Minute
+
+// idempotencyWriter keeps a copy of the response body so it can be stored for replays
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency Middleware makes POST requests carrying an Idempotency-Key header safe to retry.
+// The first request with a key runs normally and its response is stored; retries with the same key and body
+// get the stored response back, while a different request reusing the key is rejected with 422.
+// Server errors are not stored, so the client can retry them. It must run after Authentication,
+// since keys are scoped per user.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": IdempotencyHeader + " must be at most 255 characters"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, IdempotencyMaxBody))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body is too large to use with " + IdempotencyHeader})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Could not read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := models.IdempotencyRecord{
+			ID:          primitive.NewObjectID(),
+			Key:         key,
+			UserID:      c.GetString("uid"),
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: idempotencyRequestHash(c.Request, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(IdempotencyTTL),
+		}
+
+		if !claimIdempotencyKey(ctx, c, record) {
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// The handler's own context may be gone by now; storing the response gets a fresh one
+		storeCtx, storeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer storeCancel()
+
+		filter := bson.M{"_id": record.ID}
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			if _, err := database.IdempotencyCollection.DeleteOne(storeCtx, filter); err != nil {
+				log.Printf("idempotency key %q: releasing failed request: %v", key, err)
+			}
+			return
+		}
+
+		update := bson.M{"$set": bson.M{
+			"completed":    true,
+			"status_code":  status,
+			"content_type": writer.Header().Get("Content-Type"),
+			"body":         writer.body.Bytes(),
+			"expires_at":   time.Now().Add(IdempotencyTTL),
+		}}
+		if _, err := database.IdempotencyCollection.UpdateOne(storeCtx, filter, update); err != nil {
+			log.Printf("idempotency key %q: storing response: %v", key, err)
+		}
+	}
+}
+
+// idempotencyRequestHash identifies a request by its method, path with query string, and body
+func idempotencyRequestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// claimIdempotencyKey stores the record for a new key. For a key that was seen before it answers the request
+// itself (replay, 409 while the first request is running, 422 for a different request) and returns false.
+func claimIdempotencyKey(ctx context.Context, c *gin.Context, record models.IdempotencyRecord) bool {
+	_, err := database.IdempotencyCollection.InsertOne(ctx, record)
+	if err == nil {
+		return true
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not check " + IdempotencyHeader})
+		return false
+	}
+
+	var existing models.IdempotencyRecord
+	err = database.IdempotencyCollection.FindOne(ctx, bson.M{"user_id": record.UserID, "key": record.Key}).Decode(&existing)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// The first request failed and released the key in the meantime
+		return claimIdempotencyKey(ctx, c, record)
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not check " + IdempotencyHeader})
+		return false
+	}
+
+	if existing.RequestHash != record.RequestHash {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": IdempotencyHeader + " was already used for a different request"})
+		return false
+	}
+
+	if existing.Completed {
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+		c.Abort()
+		return false
+	}
+
+	// A request that has held the key for too long has crashed; take the key over
+	if time.Since(existing.CreatedAt) > idempotencyLockTimeout {
+		filter := bson.M{"_id": existing.ID, "completed": false}
+		if _, err := database.IdempotencyCollection.DeleteOne(ctx, filter); err == nil {
+			return claimIdempotencyKey(ctx, c, record)
+		}
+	}
+
+	c.Header("Retry-After", "1")
+	c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A request with this " + IdempotencyHeader + " is still being processed"})
+	return false
+}
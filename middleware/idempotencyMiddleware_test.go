@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotencyRequestHash(t *testing.T) {
+	hash := func(target, body string) string {
+		return idempotencyRequestHash(httptest.NewRequest(http.MethodPost, target, nil), []byte(body))
+	}
+
+	if hash("/menus/import?dry_run=true", "{}") == hash("/menus/import", "{}") {
+		t.Error("a dry run and the real request hash the same")
+	}
+	if hash("/orders", `{"a":1}`) == hash("/orders", `{"a":2}`) {
+		t.Error("different bodies hash the same")
+	}
+	if hash("/orders?x=1", "{}") != hash("/orders?x=1", "{}") {
+		t.Error("the same request hashes differently")
+	}
+}
+
+func TestIdempotencyRejectsLargeBodies(t *testing.T) {
+	defer func(limit int64) { IdempotencyMaxBody = limit }(IdempotencyMaxBody)
+	IdempotencyMaxBody = 16
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Idempotency())
+	router.POST("/orders", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(strings.Repeat("x", 17)))
+	req.Header.Set(IdempotencyHeader, "key-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status %d, want 413", w.Code)
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"golang-restaurant-management/database"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// RequireRole Middleware only lets authenticated users with one of the given roles through.
+// It must run after Authentication, which stores the user's uid in the context.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		uid := c.GetString("uid")
+		if uid == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No Authorization token provided"})
+			return
+		}
+
+		// Roles are read from the database so demotions apply without waiting for token expiry
+		var user struct {
+			Role string `bson:"role"`
+		}
+		err := database.UserCollection.FindOne(ctx, bson.M{"user_id": uid}).Decode(&user)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "User not found"})
+			return
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				c.Set("role", user.Role)
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to perform this action"})
+	}
+}
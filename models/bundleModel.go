@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"golang-restaurant-management/money"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Bundle struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`                                     //? Unique bundle ID (MongoDB ObjectID)
+	BundleID    string             `bson:"bundle_id" json:"bundle_id"`                        //? Unique bundle identifier
+	Name        *string            `bson:"name" json:"name" validate:"required"`              //? Name of the bundle (e.g. "Burger Meal")
+	Description *string            `bson:"description,omitempty" json:"description"`          //? Short description of the bundle
+	Price       *money.Money       `bson:"price" json:"price" validate:"required,gt=0"`       //? Base price of the bundle with default choices
+	MenuID      *string            `bson:"menu_id" json:"menu_id" validate:"required"`        //? Menu the bundle is listed on
+	Slots       []BundleSlot       `bson:"slots" json:"slots" validate:"required,min=1,dive"` //? Component slots making up the bundle
+	Available   *bool              `bson:"available,omitempty" json:"available"`              //? Whether the bundle can currently be ordered (nil = available)
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`                      //? Timestamp when the bundle was created
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`                      //? Timestamp when the bundle was last updated
+}
+
+type BundleSlot struct {
+	Name          string         `bson:"name" json:"name" validate:"required"`                       //? Slot name shown to guests and the kitchen (e.g. "Side")
+	DefaultFoodID string         `bson:"default_food_id" json:"default_food_id" validate:"required"` //? Food served when no substitution is chosen
+	Quantity      int            `bson:"quantity" json:"quantity" validate:"gte=0"`                  //? Portions of the slot's food (0 = 1)
+	Options       []BundleOption `bson:"options" json:"options" validate:"dive"`                     //? Allowed substitutions for the default food
+}
+
+type BundleOption struct {
+	FoodID   string      `bson:"food_id" json:"food_id" validate:"required"` //? Food that may replace the slot default
+	Upcharge money.Money `bson:"upcharge" json:"upcharge" validate:"gte=0"`  //? Extra charge for choosing this food
+}
+
+type BundleSelection struct {
+	Slot     string      `bson:"slot" json:"slot" validate:"required"` //? Name of the bundle slot
+	FoodID   string      `bson:"food_id" json:"food_id"`               //? Chosen food (defaults to the slot default)
+	Quantity int         `bson:"quantity" json:"quantity"`             //? Portions of the chosen food
+	Upcharge money.Money `bson:"upcharge" json:"upcharge"`             //? Upcharge applied for the choice
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Category struct {
+	ID           primitive.ObjectID     `bson:"_id,omitempty"`                                        //? Unique category ID (MongoDB ObjectID)
+	CategoryID   string                 `bson:"category_id" json:"category_id"`                       //? Unique category identifier
+	MenuID       *string                `bson:"menu_id" json:"menu_id" validate:"required"`           //? Menu the category belongs to
+	ParentID     *string                `bson:"parent_id" json:"parent_id"`                           //? Parent category (nil for top level, e.g. Drinks > Hot > Coffee)
+	Name         *string                `bson:"name" json:"name" validate:"required"`                 //? Name of the category
+	Position     *int                   `bson:"position" json:"position"`                             //? Display position among its siblings
+	Translations map[string]Translation `bson:"translations,omitempty" json:"translations,omitempty"` //? Localised content keyed by locale (e.g. "es")
+	CreatedAt    time.Time              `bson:"created_at" json:"created_at"`                         //? Timestamp when the category was created
+	UpdatedAt    time.Time              `bson:"updated_at" json:"updated_at"`                         //? Timestamp when the category was last updated
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FloorArea struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`                         //? Unique floor area ID (MongoDB ObjectID)
+	AreaID    string             `bson:"area_id" json:"area_id"`                //? Unique floor area identifier
+	Name      *string            `bson:"name" json:"name" validate:"required"`  //? Name of the area (e.g. "Patio", "Main", "Bar")
+	Position  *int               `bson:"position" json:"position"`              //? Display order of the area tabs
+	Width     float64            `bson:"width" json:"width" validate:"gte=0"`   //? Width of the area canvas in floor-plan units
+	Height    float64            `bson:"height" json:"height" validate:"gte=0"` //? Height of the area canvas in floor-plan units
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`          //? Timestamp when the area was created
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`          //? Timestamp when the area was last updated
+}
+
+type TableLayout struct {
+	X        float64 `bson:"x" json:"x" validate:"gte=0"`                                        //? Left edge of the table on the area canvas
+	Y        float64 `bson:"y" json:"y" validate:"gte=0"`                                        //? Top edge of the table on the area canvas
+	Width    float64 `bson:"width" json:"width" validate:"gt=0"`                                 //? Width of the table shape
+	Height   float64 `bson:"height" json:"height" validate:"gt=0"`                               //? Height of the table shape
+	Rotation float64 `bson:"rotation" json:"rotation" validate:"gte=0,lt=360"`                   //? Clockwise rotation in degrees
+	Shape    string  `bson:"shape" json:"shape" validate:"required,oneof=rectangle round booth"` //? Shape used to draw the table
+}
+
+type Section struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`                        //? Unique section ID (MongoDB ObjectID)
+	SectionID string             `bson:"section_id" json:"section_id"`         //? Unique section identifier
+	Name      *string            `bson:"name" json:"name" validate:"required"` //? Name of the section (e.g. "Patio left")
+	AreaID    *string            `bson:"area_id" json:"area_id"`               //? Floor area the section lies in
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`         //? Timestamp when the section was created
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`         //? Timestamp when the section was last updated
+}
+
+type SectionAssignment struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`                                  //? Unique assignment ID (MongoDB ObjectID)
+	AssignmentID string             `bson:"assignment_id" json:"assignment_id"`             //? Unique assignment identifier
+	SectionID    string             `bson:"section_id" json:"section_id"`                   //? Section the server covers
+	UserID       *string            `bson:"user_id" json:"user_id" validate:"required"`     //? Staff user serving the section
+	StartsAt     *time.Time         `bson:"starts_at" json:"starts_at" validate:"required"` //? Start of the shift
+	EndsAt       *time.Time         `bson:"ends_at" json:"ends_at" validate:"required"`     //? End of the shift
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`                   //? Timestamp when the assignment was created
+}
@@ -3,16 +3,28 @@ package models
 import (
 	"time"
 
+	"golang-restaurant-management/money"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Food struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`                  //? Unique food ID (MongoDB ObjectID)
-	Name      *string            `json:"name" validate:"required"`       //? Name of the food item
-	Price     *float64           `json:"price" validate:"required,gt=0"` //? Price of the food item (must be greater than 0)
-	FoodImage *string            `json:"image"`                          //? Image URL of the food item
-	MenuID    *string            `json:"menu_id" validate:"required"`    //? Associated menu ID
-	FoodID    string             `json:"food_id"`                        //? Unique food identifier
-	CreatedAt time.Time          `json:"created_at"`                     //? Timestamp when the food item was created
-	UpdatedAt time.Time          `json:"updated_at"`                     //? Timestamp when the food item was last updated
+	ID           primitive.ObjectID     `bson:"_id,omitempty"`                                             //? Unique food ID (MongoDB ObjectID)
+	Name         *string                `bson:"name" json:"name" validate:"required"`                      //? Name of the food item
+	Description  *string                `bson:"description,omitempty" json:"description"`                  //? Short description used for search and display
+	Price        *money.Money           `bson:"price" json:"price" validate:"required,gt=0"`               //? Price of the food item (must be greater than 0)
+	FoodImage    *string                `bson:"food_image" json:"image"`                                   //? Image URL of the food item
+	Thumbnail    *string                `bson:"thumbnail,omitempty" json:"thumbnail"`                      //? Thumbnail URL generated from an uploaded image
+	MenuID       *string                `bson:"menu_id" json:"menu_id" validate:"required"`                //? Associated menu ID
+	CategoryID   *string                `bson:"category_id,omitempty" json:"category_id"`                  //? Category the food is listed under
+	StationID    *string                `bson:"station_id,omitempty" json:"station_id"`                    //? Kitchen station that prepares the food
+	Position     *int                   `bson:"position,omitempty" json:"position"`                        //? Display position within its category
+	Available    *bool                  `bson:"available,omitempty" json:"available"`                      //? Whether the item can currently be ordered (nil = available)
+	Nutrition    *NutritionFacts        `bson:"nutrition,omitempty" json:"nutrition" validate:"omitempty"` //? Nutritional facts for calorie labelling
+	SizeFactors  map[string]float64     `bson:"size_factors,omitempty" json:"size_factors"`                //? Portion multiplier per size (S/M/L), defaults apply when unset
+	Modifiers    []FoodModifier         `bson:"modifiers,omitempty" json:"modifiers" validate:"dive"`      //? Optional modifiers guests can choose
+	Translations map[string]Translation `bson:"translations,omitempty" json:"translations,omitempty"`      //? Localised content keyed by locale (e.g. "es")
+	FoodID       string                 `bson:"food_id" json:"food_id"`                                    //? Unique food identifier
+	CreatedAt    time.Time              `bson:"created_at" json:"created_at"`                              //? Timestamp when the food item was created
+	UpdatedAt    time.Time              `bson:"updated_at" json:"updated_at"`                              //? Timestamp when the food item was last updated
 }
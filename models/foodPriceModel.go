@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"golang-restaurant-management/money"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FoodPrice struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`                                    //? Unique price record ID (MongoDB ObjectID)
+	PriceID     string             `bson:"price_id" json:"price_id"`                         //? Unique price record identifier
+	FoodID      string             `bson:"food_id" json:"food_id"`                           //? Food item this price belongs to
+	Price       *money.Money       `bson:"price" json:"price" validate:"required,gt=0"`      //? Price of the food item from EffectiveAt on
+	EffectiveAt time.Time          `bson:"effective_at" json:"effective_at"`                 //? Moment this price takes effect (may be in the future)
+	Version     int                `bson:"version" json:"version"`                           //? Sequential version number per food item
+	AppliedAt   *time.Time         `bson:"applied_at,omitempty" json:"applied_at,omitempty"` //? When the price was copied onto the food item
+	CreatedBy   string             `bson:"created_by,omitempty" json:"created_by,omitempty"` //? User that recorded the price
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`                     //? Timestamp when the price was recorded
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IdempotencyRecord struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`                    //? Unique record ID (MongoDB ObjectID)
+	Key         string             `bson:"key" json:"key"`                   //? Value of the Idempotency-Key header
+	UserID      string             `bson:"user_id" json:"user_id"`           //? User the key belongs to; keys are scoped per user
+	Method      string             `bson:"method" json:"method"`             //? HTTP method of the original request
+	Path        string             `bson:"path" json:"path"`                 //? Path of the original request
+	RequestHash string             `bson:"request_hash" json:"request_hash"` //? SHA-256 of method, path and body, used to detect reused keys
+	Completed   bool               `bson:"completed" json:"completed"`       //? Whether the original request has finished
+	StatusCode  int                `bson:"status_code" json:"status_code"`   //? Status of the stored response
+	ContentType string             `bson:"content_type" json:"content_type"` //? Content type of the stored response
+	Body        []byte             `bson:"body" json:"-"`                    //? Stored response body, replayed as is
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`     //? When the original request started
+	ExpiresAt   time.Time          `bson:"expires_at" json:"expires_at"`     //? When the record is removed by the TTL index
+}
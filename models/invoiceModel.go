@@ -7,12 +7,12 @@ import (
 )
 
 type Invoice struct {
-	ID             primitive.ObjectID `bson:"_id,omitempty"`                                                //? Unique invoice ID (MongoDB ObjectID)
-	InvoiceID      string             `json:"invoice_id" validate:"required"`                               //? Invoice ID as a string
-	OrderID        string             `json:"order_id" validate:"required"`                                 //? Associated order ID
-	PaymentMethod  *string            `json:"payment_method" validate:"required,oneof=cash card upi"`       //? Payment method used
-	PaymentStatus  *string            `json:"payment_status" validate:"required,oneof=pending paid failed"` //? Status of the payment
-	PaymentDueDate time.Time          `json:"payment_due_date"`                                             //? Due date for the payment
-	CreatedAt      time.Time          `json:"created_at"`                                                   //? Timestamp when the invoice was created
-	UpdatedAt      time.Time          `json:"updated_at"`                                                   //? Timestamp when the invoice was last updated
+	ID             primitive.ObjectID `bson:"_id,omitempty"`                                                                      //? Unique invoice ID (MongoDB ObjectID)
+	InvoiceID      string             `bson:"invoice_id" json:"invoice_id" validate:"required"`                                   //? Invoice ID as a string
+	OrderID        string             `bson:"order_id" json:"order_id" validate:"required"`                                       //? Associated order ID
+	PaymentMethod  *string            `bson:"payment_method" json:"payment_method" validate:"required,oneof=cash card upi"`       //? Payment method used
+	PaymentStatus  *string            `bson:"payment_status" json:"payment_status" validate:"required,oneof=pending paid failed"` //? Status of the payment
+	PaymentDueDate time.Time          `bson:"payment_due_date" json:"payment_due_date"`                                           //? Due date for the payment
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`                                                       //? Timestamp when the invoice was created
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`                                                       //? Timestamp when the invoice was last updated
 }
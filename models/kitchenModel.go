@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type KitchenStation struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`                                                            //? Unique station ID (MongoDB ObjectID)
+	StationID     string             `bson:"station_id" json:"station_id"`                                             //? Unique station identifier
+	Name          *string            `bson:"name" json:"name" validate:"required"`                                     //? Name of the station (e.g. "Grill", "Fry", "Bar", "Cold")
+	Default       *bool              `bson:"default,omitempty" json:"default"`                                         //? Receives the items of foods without a station
+	TargetMinutes *int               `bson:"target_minutes,omitempty" json:"target_minutes" validate:"omitempty,gt=0"` //? Items older than this are flagged as late on the screen
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`                                             //? Timestamp when the station was created
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`                                             //? Timestamp when the station was last updated
+}
+
+type KitchenTicket struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty"`                            //? Unique ticket ID (MongoDB ObjectID)
+	TicketID   string              `bson:"ticket_id" json:"ticket_id"`               //? Unique ticket identifier
+	OrderID    string              `bson:"order_id" json:"order_id"`                 //? Order the ticket was fired for
+	StationID  string              `bson:"station_id" json:"station_id"`             //? Station whose screen shows the ticket
+	TableID    *string             `bson:"table_id,omitempty" json:"table_id"`       //? Table of a dine-in order
+	OrderType  string              `bson:"order_type" json:"order_type"`             //? dine_in, takeout or delivery
+	Status     string              `bson:"status" json:"status"`                     //? open while any item is cooking, bumped when all are done
+	Items      []KitchenTicketItem `bson:"items" json:"items"`                       //? Lines the station has to prepare
+	BumpedAt   *time.Time          `bson:"bumped_at,omitempty" json:"bumped_at"`     //? When the last item was bumped
+	RecalledAt *time.Time          `bson:"recalled_at,omitempty" json:"recalled_at"` //? When the ticket was last brought back
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`             //? When the items were submitted to the kitchen
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`             //? Timestamp when the ticket was last updated
+}
+
+type KitchenTicketItem struct {
+	LineID      string     `bson:"line_id" json:"line_id"`                   //? Unique identifier of the line within the kitchen
+	OrderItemID string     `bson:"order_item_id" json:"order_item_id"`       //? Order item the line was fired from
+	FoodID      string     `bson:"food_id" json:"food_id"`                   //? Food to prepare
+	Name        string     `bson:"name" json:"name"`                         //? Name of the food
+	Size        string     `bson:"size,omitempty" json:"size,omitempty"`     //? Portion size (S/M/L)
+	Portions    int        `bson:"portions" json:"portions"`                 //? Number of portions
+	Bundle      string     `bson:"bundle,omitempty" json:"bundle,omitempty"` //? Combo meal the line belongs to
+	Slot        string     `bson:"slot,omitempty" json:"slot,omitempty"`     //? Combo meal slot the line fills
+	Modifiers   []string   `bson:"modifiers,omitempty" json:"modifiers"`     //? Modifiers chosen by the guest
+	Status      string     `bson:"status" json:"status"`                     //? cooking or done
+	StartedAt   time.Time  `bson:"started_at" json:"started_at"`             //? When the line reached the station
+	BumpedAt    *time.Time `bson:"bumped_at,omitempty" json:"bumped_at"`     //? When the line was bumped as done
+	RecalledAt  *time.Time `bson:"recalled_at,omitempty" json:"recalled_at"` //? When the line was last brought back after a bump
+}
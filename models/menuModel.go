@@ -7,12 +7,15 @@ import (
 )
 
 type Menu struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`                //? Unique menu ID (MongoDB ObjectID)
-	MenuID    string             `json:"menu_id" validate:"required"`  //? Unique menu identifier
-	Name      string             `json:"name" validate:"required"`     //? Name of the menu
-	Category  string             `json:"category" validate:"required"` //? Category of the menu
-	StartDate *time.Time         `json:"start_date"`                   //? Start date of menu availability
-	EndDate   *time.Time         `json:"end_date"`                     //? End date of menu availability
-	CreatedAt time.Time          `json:"created_at"`                   //? Timestamp when the menu was created
-	UpdatedAt time.Time          `json:"updated_at"`                   //? Timestamp when the menu was last updated
+	ID               primitive.ObjectID     `bson:"_id,omitempty"`                                        //? Unique menu ID (MongoDB ObjectID)
+	MenuID           string                 `bson:"menu_id" json:"menu_id" validate:"required"`           //? Unique menu identifier
+	Name             string                 `bson:"name" json:"name" validate:"required"`                 //? Name of the menu
+	Category         string                 `bson:"category" json:"category" validate:"required"`         //? Category of the menu
+	StartDate        *time.Time             `bson:"start_date" json:"start_date"`                         //? Start date of menu availability
+	EndDate          *time.Time             `bson:"end_date" json:"end_date"`                             //? End date of menu availability
+	Translations     map[string]Translation `bson:"translations,omitempty" json:"translations,omitempty"` //? Localised content keyed by locale (e.g. "es")
+	PublishedVersion int                    `bson:"published_version,omitempty" json:"published_version"` //? Version currently live (0 = never published through the draft workflow)
+	PublishedAt      *time.Time             `bson:"published_at,omitempty" json:"published_at"`           //? When the live version was published
+	CreatedAt        time.Time              `bson:"created_at" json:"created_at"`                         //? Timestamp when the menu was created
+	UpdatedAt        time.Time              `bson:"updated_at" json:"updated_at"`                         //? Timestamp when the menu was last updated
 }
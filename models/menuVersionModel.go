@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MenuVersion struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`                                                           //? Unique version ID (MongoDB ObjectID)
+	VersionID   string             `bson:"version_id" json:"version_id"`                                            //? Unique version identifier
+	MenuID      string             `bson:"menu_id" json:"menu_id"`                                                  //? Menu this version belongs to
+	Version     int                `bson:"version" json:"version"`                                                  //? Sequential version number (0 while still a draft)
+	Status      string             `bson:"status" json:"status" validate:"required,oneof=draft published archived"` //? draft, published (live) or archived
+	Menu        Menu               `bson:"menu" json:"menu"`                                                        //? Snapshot of the menu
+	Foods       []Food             `bson:"foods" json:"foods"`                                                      //? Snapshot of the menu's foods
+	BaseFoods   []Food             `bson:"base_foods,omitempty" json:"-"`                                           //? Live foods as they were when the draft was opened
+	CreatedBy   string             `bson:"created_by,omitempty" json:"created_by,omitempty"`                        //? User that started the draft
+	PublishedBy string             `bson:"published_by,omitempty" json:"published_by,omitempty"`                    //? User that published the version
+	PublishedAt *time.Time         `bson:"published_at,omitempty" json:"published_at,omitempty"`                    //? When the version went live
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`                                            //? Timestamp when the version was created
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`                                            //? Timestamp when the version was last updated
+}
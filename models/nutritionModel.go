@@ -0,0 +1,68 @@
+package models
+
+import "golang-restaurant-management/money"
+
+type Nutrition struct {
+	EnergyKcal     float64 `bson:"energy_kcal" json:"energy_kcal" validate:"gte=0"`         //? Energy in kilocalories
+	ProteinG       float64 `bson:"protein_g" json:"protein_g" validate:"gte=0"`             //? Protein in grams
+	CarbohydratesG float64 `bson:"carbohydrates_g" json:"carbohydrates_g" validate:"gte=0"` //? Carbohydrates in grams
+	SugarsG        float64 `bson:"sugars_g" json:"sugars_g" validate:"gte=0"`               //? Of which sugars, in grams
+	FatG           float64 `bson:"fat_g" json:"fat_g" validate:"gte=0"`                     //? Fat in grams
+	SaturatedFatG  float64 `bson:"saturated_fat_g" json:"saturated_fat_g" validate:"gte=0"` //? Of which saturates, in grams
+	FiberG         float64 `bson:"fiber_g" json:"fiber_g" validate:"gte=0"`                 //? Fibre in grams
+	SaltG          float64 `bson:"salt_g" json:"salt_g" validate:"gte=0"`                   //? Salt in grams
+}
+
+type NutritionFacts struct {
+	PortionGrams float64    `bson:"portion_grams" json:"portion_grams" validate:"gte=0"`           //? Weight of one standard (M) portion in grams
+	PerPortion   *Nutrition `bson:"per_portion,omitempty" json:"per_portion" validate:"omitempty"` //? Values for one standard portion
+	Per100g      *Nutrition `bson:"per_100g,omitempty" json:"per_100g" validate:"omitempty"`       //? Values per 100 grams
+}
+
+type FoodModifier struct {
+	Name      string      `bson:"name" json:"name" validate:"required"` //? Modifier name (e.g. "extra cheese", "no bun")
+	Upcharge  money.Money `bson:"upcharge" json:"upcharge"`             //? Price added (or removed, if negative) when chosen
+	Nutrition Nutrition   `bson:"nutrition" json:"nutrition"`           //? Change to the per-portion values when chosen (may be negative)
+}
+
+// Add returns the sum of two sets of values
+func (n Nutrition) Add(other Nutrition) Nutrition {
+	return Nutrition{
+		EnergyKcal:     n.EnergyKcal + other.EnergyKcal,
+		ProteinG:       n.ProteinG + other.ProteinG,
+		CarbohydratesG: n.CarbohydratesG + other.CarbohydratesG,
+		SugarsG:        n.SugarsG + other.SugarsG,
+		FatG:           n.FatG + other.FatG,
+		SaturatedFatG:  n.SaturatedFatG + other.SaturatedFatG,
+		FiberG:         n.FiberG + other.FiberG,
+		SaltG:          n.SaltG + other.SaltG,
+	}
+}
+
+// NonNegative returns the values with negatives raised to zero, e.g. after a modifier removed more than there was
+func (n Nutrition) NonNegative() Nutrition {
+	return Nutrition{
+		EnergyKcal:     max(n.EnergyKcal, 0),
+		ProteinG:       max(n.ProteinG, 0),
+		CarbohydratesG: max(n.CarbohydratesG, 0),
+		SugarsG:        max(n.SugarsG, 0),
+		FatG:           max(n.FatG, 0),
+		SaturatedFatG:  max(n.SaturatedFatG, 0),
+		FiberG:         max(n.FiberG, 0),
+		SaltG:          max(n.SaltG, 0),
+	}
+}
+
+// Scale returns the values multiplied by factor
+func (n Nutrition) Scale(factor float64) Nutrition {
+	return Nutrition{
+		EnergyKcal:     n.EnergyKcal * factor,
+		ProteinG:       n.ProteinG * factor,
+		CarbohydratesG: n.CarbohydratesG * factor,
+		SugarsG:        n.SugarsG * factor,
+		FatG:           n.FatG * factor,
+		SaturatedFatG:  n.SaturatedFatG * factor,
+		FiberG:         n.FiberG * factor,
+		SaltG:          n.SaltG * factor,
+	}
+}
@@ -3,16 +3,22 @@ package models
 import (
 	"time"
 
+	"golang-restaurant-management/money"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type OrderItem struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty"`                      //? Unique order item ID (MongoDB ObjectID)
-	Quantity    *string              `json:"quantity" validate:"required,eq=S | eq=M | eq=L"` //? Quantity of the item
-	UnitPrice   *float64           `json:"unit_price" validate:"required"`     //? Price per unit of the item
-	OrderID     string             `json:"order_id" validate:"required"`       //? Associated order ID
-	OrderItemID string             `json:"order_item_id" validate:"required"`  //? Unique order item identifier
-	FoodID      *string             `json:"food_id" validate:"required"`        //? Associated food item ID
-	CreatedAt   time.Time          `json:"created_at"`                         //? Timestamp when the order item was created
-	UpdatedAt   time.Time          `json:"updated_at"`                         //? Timestamp when the order item was last updated
+	ID            primitive.ObjectID `bson:"_id,omitempty"`                                                   //? Unique order item ID (MongoDB ObjectID)
+	Quantity      *string            `bson:"quantity" json:"quantity" validate:"required,eq=S | eq=M | eq=L"` //? Quantity of the item
+	UnitPrice     *money.Money       `bson:"unit_price" json:"unit_price" validate:"required"`                //? Price per unit of the item
+	OrderID       string             `bson:"order_id" json:"order_id" validate:"required"`                    //? Associated order ID
+	OrderItemID   string             `bson:"order_item_id" json:"order_item_id" validate:"required"`          //? Unique order item identifier
+	FoodID        *string            `bson:"food_id" json:"food_id" validate:"required_without=BundleID"`     //? Associated food item ID
+	BundleID      *string            `bson:"bundle_id,omitempty" json:"bundle_id"`                            //? Associated bundle ID when the line is a combo meal
+	Selections    []BundleSelection  `bson:"bundle_selections,omitempty" json:"bundle_selections"`            //? Chosen food per bundle slot
+	Modifiers     []string           `bson:"modifiers,omitempty" json:"modifiers"`                            //? Names of the food modifiers chosen for this line
+	PricingRuleID *string            `bson:"pricing_rule_id,omitempty" json:"pricing_rule_id"`                //? Pricing rule applied to the unit price, if any
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`                                    //? Timestamp when the order item was created
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`                                    //? Timestamp when the order item was last updated
 }
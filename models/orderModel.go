@@ -3,14 +3,42 @@ package models
 import (
 	"time"
 
+	"golang-restaurant-management/money"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Order struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`                  //? Unique order ID (MongoDB ObjectID)
-	OrderID   string             `json:"order_id"`                       //? Order ID as a string
-	TableID   *string            `json:"table_id"`                       //? ID of the table associated with this order
-	OrderDate time.Time          `json:"order_date" validate:"required"` //? Time when order was placed
-	CreatedAt time.Time          `json:"created_at"`                     //? Timestamp when order was created
-	UpdatedAt time.Time          `json:"updated_at"`                     //? Timestamp when order was last updated
+	ID              primitive.ObjectID  `bson:"_id,omitempty"`                                                                  //? Unique order ID (MongoDB ObjectID)
+	OrderID         string              `bson:"order_id" json:"order_id"`                                                       //? Order ID as a string
+	TableID         *string             `bson:"table_id" json:"table_id"`                                                       //? ID of the table associated with this order
+	OrderDate       time.Time           `bson:"order_date" json:"order_date" validate:"required"`                               //? Time when order was placed
+	Type            string              `bson:"type,omitempty" json:"type" validate:"omitempty,oneof=dine_in takeout delivery"` //? dine_in (default), takeout or delivery
+	Customer        *OrderCustomer      `bson:"customer,omitempty" json:"customer"`                                             //? Who the order is for; required for takeout
+	PickupAt        *time.Time          `bson:"pickup_at,omitempty" json:"pickup_at"`                                           //? When a takeout order will be collected
+	Delivery        *OrderDelivery      `bson:"delivery,omitempty" json:"delivery"`                                             //? Where a delivery order goes and what the delivery costs
+	Status          string              `bson:"status,omitempty" json:"status"`                                                 //? Lifecycle status (open, submitted, in_kitchen, ready, served, paid, closed, cancelled)
+	StatusUpdatedAt *time.Time          `bson:"status_updated_at,omitempty" json:"status_updated_at"`                           //? When the status last changed
+	StatusHistory   []OrderStatusChange `bson:"status_history,omitempty" json:"status_history"`                                 //? Every status change, oldest first
+	PaidAt          *time.Time          `bson:"paid_at,omitempty" json:"paid_at"`                                               //? When the bill was paid, which may be before the food is out
+	CreatedAt       time.Time           `bson:"created_at" json:"created_at"`                                                   //? Timestamp when order was created
+	UpdatedAt       time.Time           `bson:"updated_at" json:"updated_at"`                                                   //? Timestamp when order was last updated
+}
+
+type OrderStatusChange struct {
+	From string    `bson:"from,omitempty" json:"from,omitempty"` //? Status before the change (empty for the initial status)
+	To   string    `bson:"to" json:"to"`                         //? Status after the change
+	At   time.Time `bson:"at" json:"at"`                         //? When the change happened
+	By   string    `bson:"by,omitempty" json:"by,omitempty"`     //? User who made the change, empty for automatic ones
+}
+
+type OrderCustomer struct {
+	Name  *string `bson:"name" json:"name" validate:"required,min=1,max=100"`  //? Name the order is called out under
+	Phone *string `bson:"phone" json:"phone" validate:"required,min=5,max=20"` //? Contact phone number
+}
+
+type OrderDelivery struct {
+	Address *string      `bson:"address" json:"address" validate:"required,min=5,max=300"`  //? Delivery address
+	Notes   *string      `bson:"notes,omitempty" json:"notes" validate:"omitempty,max=300"` //? Directions for the driver
+	Fee     *money.Money `bson:"fee" json:"fee" validate:"required"`                        //? Delivery fee charged on top of the items
 }
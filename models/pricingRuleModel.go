@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"golang-restaurant-management/money"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PricingRule struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`                                                              //? Unique pricing rule ID (MongoDB ObjectID)
+	RuleID     string             `bson:"rule_id" json:"rule_id"`                                                     //? Unique pricing rule identifier
+	Name       *string            `bson:"name" json:"name" validate:"required"`                                       //? Name of the rule (e.g. "Happy Hour")
+	Scope      string             `bson:"scope" json:"scope" validate:"required,oneof=food menu category"`            //? What the target IDs refer to
+	TargetIDs  []string           `bson:"target_ids" json:"target_ids" validate:"required,min=1,dive,required"`       //? Foods, menus or categories the rule applies to
+	Days       []int              `bson:"days,omitempty" json:"days" validate:"dive,min=0,max=6"`                     //? Days of week the rule runs (0 = Sunday, empty = every day)
+	StartTime  string             `bson:"start_time,omitempty" json:"start_time" validate:"omitempty,datetime=15:04"` //? Daily window start in restaurant time (HH:MM)
+	EndTime    string             `bson:"end_time,omitempty" json:"end_time" validate:"omitempty,datetime=15:04"`     //? Daily window end (HH:MM, may wrap past midnight)
+	ValidFrom  *time.Time         `bson:"valid_from,omitempty" json:"valid_from"`                                     //? First moment the rule may apply
+	ValidUntil *time.Time         `bson:"valid_until,omitempty" json:"valid_until"`                                   //? Moment after which the rule no longer applies
+	Adjustment string             `bson:"adjustment" json:"adjustment" validate:"required,oneof=percent fixed"`       //? Kind of price adjustment
+	Percent    float64            `bson:"percent,omitempty" json:"percent" validate:"gte=-100,lte=1000"`              //? Percentage change for percent rules (-20 = 20% off)
+	Amount     *money.Money       `bson:"amount,omitempty" json:"amount"`                                             //? Amount added for fixed rules (negative = discount)
+	Priority   int                `bson:"priority" json:"priority"`                                                   //? Higher priority wins when several rules match
+	Active     *bool              `bson:"active,omitempty" json:"active"`                                             //? Whether the rule is enabled (nil = active)
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`                                               //? Timestamp when the rule was created
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`                                               //? Timestamp when the rule was last updated
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Reservation struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty"`                                                     //? Unique reservation ID (MongoDB ObjectID)
+	ReservationID    string             `bson:"reservation_id" json:"reservation_id"`                              //? Unique reservation identifier
+	GuestName        *string            `bson:"guest_name" json:"guest_name" validate:"required,min=1,max=100"`    //? Name the booking is under
+	Phone            *string            `bson:"phone" json:"phone" validate:"required,min=5,max=20"`               //? Contact phone number of the guest
+	PartySize        *int               `bson:"party_size" json:"party_size" validate:"required,gt=0"`             //? Number of guests
+	ReservedAt       *time.Time         `bson:"reserved_at" json:"time" validate:"required"`                       //? Start of the booking
+	DurationMinutes  int                `bson:"duration_minutes" json:"duration_minutes" validate:"gte=0,lte=480"` //? Expected length of the visit (0 = default)
+	EndsAt           time.Time          `bson:"ends_at" json:"ends_at"`                                            //? End of the booking (start + duration), used for overlap checks
+	Notes            *string            `bson:"notes,omitempty" json:"notes" validate:"omitempty,max=500"`         //? Special requests (allergies, high chair, ...)
+	Status           string             `bson:"status" json:"status"`                                              //? booked, seated, cancelled or no_show
+	TableID          *string            `bson:"table_id" json:"table_id"`                                          //? Table assigned to the booking
+	CapacityOverride bool               `bson:"capacity_override,omitempty" json:"capacity_override"`              //? Book the table even if the party is outside its capacity (managers only)
+	OverriddenBy     *string            `bson:"overridden_by,omitempty" json:"overridden_by"`                      //? Manager who overrode the capacity check
+	OrderID          *string            `bson:"order_id,omitempty" json:"order_id"`                                //? Order opened when the party was seated
+	SeatedAt         *time.Time         `bson:"seated_at,omitempty" json:"seated_at"`                              //? When the party was seated
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`                                      //? Timestamp when the reservation was created
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`                                      //? Timestamp when the reservation was last updated
+}
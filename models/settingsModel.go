@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Settings struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`                                                                  //? Unique settings ID (MongoDB ObjectID)
+	SettingsID   string             `bson:"settings_id" json:"settings_id"`                                                 //? Settings document identifier (one per restaurant)
+	Currency     string             `bson:"currency" json:"currency" validate:"required,iso4217"`                           //? ISO 4217 currency all prices are kept in
+	RoundingMode string             `bson:"rounding_mode" json:"rounding_mode" validate:"required,oneof=half_up half_even"` //? Rounding rule for prices and adjustments
+	TableQRKey   string             `bson:"table_qr_key,omitempty" json:"-"`                                                //? Secret signing table QR links (rotate to invalidate every sticker)
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`                                                   //? Timestamp when the settings were created
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`                                                   //? Timestamp when the settings were last updated
+}
@@ -7,10 +7,20 @@ import (
 )
 
 type Table struct {
-	ID             primitive.ObjectID `bson:"_id,omitempty"`                        //? Unique table ID (MongoDB ObjectID)
-	NumberOfGuests *int               `json:"number_of_guests" validate:"required"` //? Number of guests at the table
-	TableNumber    *int               `json:"table_number" validate:"required"`     //? Table number
-	CreatedAt      time.Time          `json:"created_at"`                           //? Timestamp when the table was created
-	UpdatedAt      time.Time          `json:"updated_at"`                           //? Timestamp when the table was last updated
-	TableID        string             `json:"table_id"`                             //? Unique table identifier as a string
+	ID              primitive.ObjectID `bson:"_id,omitempty"`                                                         //? Unique table ID (MongoDB ObjectID)
+	NumberOfGuests  *int               `bson:"number_of_guests" json:"number_of_guests" validate:"required"`          //? Number of guests at the table
+	TableNumber     *int               `bson:"table_number" json:"table_number" validate:"required"`                  //? Table number, unique within its floor area
+	MinCapacity     *int               `bson:"min_capacity,omitempty" json:"min_capacity" validate:"omitempty,gte=1"` //? Smallest party the table is given to (default 1)
+	MaxCapacity     *int               `bson:"max_capacity,omitempty" json:"max_capacity" validate:"omitempty,gte=1"` //? Largest party the table seats (default number_of_guests)
+	QRVersion       int                `bson:"qr_version" json:"qr_version"`                                          //? Version signed into the table's QR link (bump to invalidate its sticker)
+	Status          string             `bson:"status,omitempty" json:"status"`                                        //? Lifecycle status (available, seated, ordered, bill_requested, needs_cleaning, reserved, out_of_service)
+	StatusUpdatedAt *time.Time         `bson:"status_updated_at,omitempty" json:"status_updated_at"`                  //? When the status last changed
+	AreaID          *string            `bson:"area_id,omitempty" json:"area_id"`                                      //? Floor area the table stands in
+	SectionID       *string            `bson:"section_id,omitempty" json:"section_id"`                                //? Section (and so server) the table belongs to
+	Layout          *TableLayout       `bson:"layout,omitempty" json:"layout" validate:"omitempty"`                   //? Position and shape on the floor plan
+	CombinedFrom    []string           `bson:"combined_from,omitempty" json:"combined_from,omitempty"`                //? Tables pushed together into this temporary table
+	MergedInto      *string            `bson:"merged_into,omitempty" json:"merged_into,omitempty"`                    //? Temporary combined table this table is part of
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`                                          //? Timestamp when the table was created
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`                                          //? Timestamp when the table was last updated
+	TableID         string             `bson:"table_id" json:"table_id"`                                              //? Unique table identifier as a string
 }
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TableTransfer struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`                                  //? Unique transfer ID (MongoDB ObjectID)
+	TransferID   string             `bson:"transfer_id" json:"transfer_id"`                 //? Unique transfer identifier
+	Kind         string             `bson:"kind" json:"kind"`                               //? What moved (order, items, merge, split)
+	FromTableID  *string            `bson:"from_table_id" json:"from_table_id"`             //? Table the guests or items left
+	ToTableID    *string            `bson:"to_table_id" json:"to_table_id"`                 //? Table the guests or items moved to
+	OrderIDs     []string           `bson:"order_ids" json:"order_ids"`                     //? Orders moved, or the order the items came from
+	OrderItemIDs []string           `bson:"order_item_ids,omitempty" json:"order_item_ids"` //? Order items moved, for item transfers
+	ToOrderID    *string            `bson:"to_order_id,omitempty" json:"to_order_id"`       //? Order the items were moved into, for item transfers
+	Reason       *string            `bson:"reason,omitempty" json:"reason"`                 //? Optional note from the staff member
+	CreatedBy    string             `bson:"created_by" json:"created_by"`                   //? User who made the move
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`                   //? Timestamp of the move
+}
@@ -0,0 +1,7 @@
+package models
+
+type Translation struct {
+	Name        string `bson:"name,omitempty" json:"name,omitempty" validate:"required"` //? Translated name
+	Description string `bson:"description,omitempty" json:"description,omitempty"`       //? Translated description (foods)
+	Category    string `bson:"category,omitempty" json:"category,omitempty"`             //? Translated category (menus)
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WaitlistEntry struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`                                                  //? Unique waitlist entry ID (MongoDB ObjectID)
+	EntryID       string             `bson:"entry_id" json:"entry_id"`                                       //? Unique waitlist entry identifier
+	PartyName     *string            `bson:"party_name" json:"party_name" validate:"required,min=1,max=100"` //? Name the party is called by
+	PartySize     *int               `bson:"party_size" json:"party_size" validate:"required,gt=0"`          //? Number of guests
+	Phone         *string            `bson:"phone,omitempty" json:"phone" validate:"omitempty,min=5,max=20"` //? Phone number to notify when the table is ready
+	QuotedMinutes int                `bson:"quoted_minutes" json:"quoted_minutes"`                           //? Wait time quoted to the party when it joined
+	Status        string             `bson:"status" json:"status"`                                           //? waiting, notified, seated or left
+	TableID       *string            `bson:"table_id,omitempty" json:"table_id"`                             //? Table the party was seated at
+	NotifiedAt    *time.Time         `bson:"notified_at,omitempty" json:"notified_at"`                       //? When the party was told its table is ready
+	SeatedAt      *time.Time         `bson:"seated_at,omitempty" json:"seated_at"`                           //? When the party was seated
+	OverriddenBy  *string            `bson:"overridden_by,omitempty" json:"overridden_by"`                   //? Manager who seated the party outside the table's capacity
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`                                   //? Timestamp when the party joined the waitlist
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`                                   //? Timestamp when the entry was last updated
+}
@@ -0,0 +1,329 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// Money is an exact amount in the minor units (cents, yen, ...) of an ISO 4217 currency
+type Money struct {
+	Amount   int64  `bson:"amount" json:"amount"`     // Amount in minor units
+	Currency string `bson:"currency" json:"currency"` // ISO 4217 currency code
+}
+
+// RoundingMode decides how amounts with more precision than the currency allows are rounded
+type RoundingMode string
+
+const (
+	HalfUp   RoundingMode = "half_up"   // 0.125 -> 0.13, -0.125 -> -0.13
+	HalfEven RoundingMode = "half_even" // Banker's rounding: 0.125 -> 0.12, 0.135 -> 0.14
+)
+
+// The restaurant's currency and rounding rule. Settings can change them while requests are served,
+// so they are only read and written through DefaultCurrency, Rounding and SetDefaults.
+var (
+	defaultsMu      sync.RWMutex
+	defaultCurrency = "USD"
+	rounding        = HalfUp
+)
+
+// DefaultCurrency returns the restaurant's currency, used whenever none is given
+func DefaultCurrency() string {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return defaultCurrency
+}
+
+// Rounding returns the restaurant's rounding rule
+func Rounding() RoundingMode {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return rounding
+}
+
+// SetDefaults replaces the restaurant's currency and rounding rule
+func SetDefaults(currency string, mode RoundingMode) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaultCurrency = currency
+	rounding = mode
+}
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = errors.New("money: currency mismatch")
+
+// Number of minor unit digits per currency; anything not listed uses 2
+var exponents = map[string]int{
+	"BHD": 3, "CLP": 0, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "OMR": 3, "TND": 3, "UGX": 0, "VND": 0, "XAF": 0, "XOF": 0,
+}
+
+// ValidRoundingMode reports whether mode is a supported rounding rule
+func ValidRoundingMode(mode RoundingMode) bool {
+	return mode == HalfUp || mode == HalfEven
+}
+
+// ValidCurrency reports whether code looks like an ISO 4217 currency code
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Exponent returns the number of minor unit digits of a currency
+func Exponent(currency string) int {
+	if exp, ok := exponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+// New returns an amount of minor units in the given currency (DefaultCurrency if empty)
+func New(amount int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency()
+	}
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Zero returns a zero amount in the given currency (DefaultCurrency if empty)
+func Zero(currency string) Money {
+	return New(0, currency)
+}
+
+// Parse reads a decimal amount in major units ("12.5", "-3", "0.125") and rounds it to the currency's precision
+func Parse(value, currency string, mode RoundingMode) (Money, error) {
+	if currency == "" {
+		currency = DefaultCurrency()
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return Money{}, fmt.Errorf("money: invalid amount %q", value)
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(Exponent(currency))), nil))
+	amount, err := roundRat(r.Mul(r, scale), mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// FromFloat converts a legacy float amount in major units, using its shortest decimal representation
+func FromFloat(value float64, currency string, mode RoundingMode) (Money, error) {
+	return Parse(strconv.FormatFloat(value, 'f', -1, 64), currency, mode)
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by a whole quantity
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// MulRatio returns m * numerator / denominator rounded with mode (e.g. percentages)
+func (m Money) MulRatio(numerator, denominator int64, mode RoundingMode) (Money, error) {
+	if denominator == 0 {
+		return Money{}, errors.New("money: zero denominator")
+	}
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(numerator)), big.NewInt(denominator))
+	amount, err := roundRat(r, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// String formats the amount in major units, e.g. "12.50"
+func (m Money) String() string {
+	exp := Exponent(m.Currency)
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exp == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+	digits := fmt.Sprintf("%0*d", exp+1, amount)
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// MarshalJSON renders {"amount": minor units, "currency": code, "value": "12.50"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+		Value    string `json:"value"`
+	}{m.Amount, m.Currency, m.String()})
+}
+
+// UnmarshalJSON accepts a decimal number or string in major units (12.5, "12.50")
+// or an object with minor units ({"amount": 1250, "currency": "USD"}).
+func (m *Money) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	switch {
+	case trimmed == "null":
+		return nil
+	case strings.HasPrefix(trimmed, "{"):
+		var raw struct {
+			Amount   *int64 `json:"amount"`
+			Currency string `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		if raw.Amount == nil {
+			return errors.New("money: amount is required")
+		}
+		*m = New(*raw.Amount, strings.ToUpper(raw.Currency))
+	case strings.HasPrefix(trimmed, `"`):
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		parsed, err := Parse(value, DefaultCurrency(), Rounding())
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	default:
+		// Numbers are parsed from their literal text, never through float64
+		parsed, err := Parse(trimmed, DefaultCurrency(), Rounding())
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	}
+
+	if currency := DefaultCurrency(); m.Currency != currency {
+		return fmt.Errorf("money: currency %s does not match restaurant currency %s", m.Currency, currency)
+	}
+	return nil
+}
+
+// MarshalBSONValue stores money as an embedded document {amount: int64, currency: string}
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	doc := bsoncore.NewDocumentBuilder().
+		AppendInt64("amount", m.Amount).
+		AppendString("currency", m.Currency).
+		Build()
+	return bsontype.EmbeddedDocument, doc, nil
+}
+
+// UnmarshalBSONValue reads the embedded document form, and also legacy float/int prices
+// that have not been migrated yet (interpreted as major units of DefaultCurrency)
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bsoncore.Value{Type: t, Data: data}
+	switch t {
+	case bsontype.EmbeddedDocument:
+		var raw struct {
+			Amount   int64  `bson:"amount"`
+			Currency string `bson:"currency"`
+		}
+		if err := bson.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		*m = New(raw.Amount, raw.Currency)
+		return nil
+	case bsontype.Double:
+		parsed, err := FromFloat(value.Double(), DefaultCurrency(), Rounding())
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case bsontype.Decimal128:
+		parsed, err := Parse(value.Decimal128().String(), DefaultCurrency(), Rounding())
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case bsontype.Int32:
+		currency := DefaultCurrency()
+		*m = New(int64(value.Int32())*pow10(Exponent(currency)), currency)
+		return nil
+	case bsontype.Int64:
+		currency := DefaultCurrency()
+		*m = New(value.Int64()*pow10(Exponent(currency)), currency)
+		return nil
+	case bsontype.Null:
+		*m = Money{}
+		return nil
+	}
+	return fmt.Errorf("money: cannot decode BSON %s", t)
+}
+
+// roundRat rounds a rational number to an integer using mode
+func roundRat(r *big.Rat, mode RoundingMode) (int64, error) {
+	num, den := new(big.Int).Set(r.Num()), r.Denom()
+	negative := num.Sign() < 0
+	num.Abs(num)
+
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	twice := new(big.Int).Mul(remainder, big.NewInt(2))
+	switch cmp := twice.Cmp(den); {
+	case cmp > 0:
+		quotient.Add(quotient, big.NewInt(1))
+	case cmp == 0:
+		if mode != HalfEven || quotient.Bit(0) == 1 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, errors.New("money: amount out of range")
+	}
+	if negative {
+		quotient.Neg(quotient)
+	}
+	return quotient.Int64(), nil
+}
+
+func pow10(exp int) int64 {
+	result := int64(1)
+	for i := 0; i < exp; i++ {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package money
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		mode     RoundingMode
+		want     int64
+		wantErr  bool
+	}{
+		{"12.5", "USD", HalfUp, 1250, false},
+		{" 3 ", "USD", HalfUp, 300, false},
+		{"-3.01", "USD", HalfUp, -301, false},
+		{"1200", "JPY", HalfUp, 1200, false},
+		{"1.2345", "KWD", HalfUp, 1235, false},
+		{"0.125", "USD", HalfUp, 13, false},
+		{"0.125", "USD", HalfEven, 12, false},
+		{"abc", "USD", HalfUp, 0, true},
+		{"", "USD", HalfUp, 0, true},
+		{"100000000000000000000", "USD", HalfUp, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.value, tt.currency, tt.mode)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, want error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error %v", tt.value, err)
+			continue
+		}
+		if got.Amount != tt.want || got.Currency != tt.currency {
+			t.Errorf("Parse(%q, %s, %s) = %d %s, want %d", tt.value, tt.currency, tt.mode, got.Amount, got.Currency, tt.want)
+		}
+	}
+}
+
+func TestParseDefaultCurrency(t *testing.T) {
+	defer SetDefaults(DefaultCurrency(), Rounding())
+	SetDefaults("JPY", HalfEven)
+
+	got, err := Parse("500", "", Rounding())
+	if err != nil || got != (Money{Amount: 500, Currency: "JPY"}) {
+		t.Errorf("Parse without currency = %v, %v, want 500 JPY", got, err)
+	}
+}
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		value    string
+		halfUp   int64
+		halfEven int64
+	}{
+		{"0.125", 13, 12},
+		{"0.135", 14, 14},
+		{"0.124", 12, 12},
+		{"0.126", 13, 13},
+		{"-0.125", -13, -12},
+		{"-0.135", -14, -14},
+		{"2.5", 250, 250},
+		{"0.005", 1, 0},
+		{"0.015", 2, 2},
+	}
+	for _, tt := range tests {
+		up, err := Parse(tt.value, "USD", HalfUp)
+		if err != nil || up.Amount != tt.halfUp {
+			t.Errorf("half up %s = %d (%v), want %d", tt.value, up.Amount, err, tt.halfUp)
+		}
+		even, err := Parse(tt.value, "USD", HalfEven)
+		if err != nil || even.Amount != tt.halfEven {
+			t.Errorf("half even %s = %d (%v), want %d", tt.value, even.Amount, err, tt.halfEven)
+		}
+	}
+}
+
+func TestMulRatio(t *testing.T) {
+	tests := []struct {
+		amount      int64
+		numerator   int64
+		denominator int64
+		mode        RoundingMode
+		want        int64
+		wantErr     bool
+	}{
+		{1000, 9000, 10000, HalfUp, 900, false},
+		{999, 11000, 10000, HalfUp, 1099, false},
+		{250, 1, 100, HalfUp, 3, false},
+		{250, 1, 100, HalfEven, 2, false},
+		{350, 1, 100, HalfEven, 4, false},
+		{-250, 1, 100, HalfUp, -3, false},
+		{1000, 0, 1, HalfUp, 0, false},
+		{1 << 62, 4, 1, HalfUp, 0, true},
+		{1000, 1, 0, HalfUp, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := New(tt.amount, "USD").MulRatio(tt.numerator, tt.denominator, tt.mode)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%d * %d/%d = %v, want error", tt.amount, tt.numerator, tt.denominator, got)
+			}
+			continue
+		}
+		if err != nil || got.Amount != tt.want || got.Currency != "USD" {
+			t.Errorf("%d * %d/%d (%s) = %v (%v), want %d", tt.amount, tt.numerator, tt.denominator, tt.mode, got, err, tt.want)
+		}
+	}
+}
+
+func TestBSONRoundTrip(t *testing.T) {
+	type doc struct {
+		Price Money `bson:"price"`
+	}
+	for _, m := range []Money{New(1250, "USD"), New(-1, "USD"), New(1200, "JPY"), New(1235, "KWD")} {
+		data, err := bson.Marshal(doc{Price: m})
+		if err != nil {
+			t.Fatalf("marshal %v: %v", m, err)
+		}
+		var got doc
+		if err := bson.Unmarshal(data, &got); err != nil {
+			t.Fatalf("unmarshal %v: %v", m, err)
+		}
+		if got.Price != m {
+			t.Errorf("round trip of %v = %v", m, got.Price)
+		}
+	}
+}
+
+func TestBSONLegacyPrices(t *testing.T) {
+	defer SetDefaults(DefaultCurrency(), Rounding())
+	SetDefaults("USD", HalfEven)
+
+	decimal, err := primitive.ParseDecimal128("12.345")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		value interface{}
+		want  Money
+	}{
+		{"double", 12.5, New(1250, "USD")},
+		{"decimal128", decimal, New(1234, "USD")},
+		{"int32", int32(7), New(700, "USD")},
+		{"int64", int64(7), New(700, "USD")},
+	}
+	for _, tt := range tests {
+		data, err := bson.Marshal(bson.M{"price": tt.value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Price Money `bson:"price"`
+		}
+		if err := bson.Unmarshal(data, &got); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got.Price != tt.want {
+			t.Errorf("%s: decoded %v, want %v", tt.name, got.Price, tt.want)
+		}
+	}
+}
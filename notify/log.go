@@ -0,0 +1,18 @@
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// LogNotifier writes messages to the server log; useful in development and as a fallback
+type LogNotifier struct{}
+
+// Notify logs the message
+func (LogNotifier) Notify(ctx context.Context, message Message) error {
+	if message.To == "" {
+		return ErrNoRecipient
+	}
+	log.Printf("notify %s -> %s: %s", message.Topic, message.To, message.Body)
+	return nil
+}
@@ -0,0 +1,24 @@
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoRecipient is returned when a message has nowhere to go
+var ErrNoRecipient = errors.New("notify: message has no recipient")
+
+// Message is a short text sent to a guest (e.g. "Your table is ready")
+type Message struct {
+	To    string `json:"to"`    // Phone number or other address of the guest
+	Body  string `json:"body"`  // Text of the message
+	Topic string `json:"topic"` // What the message is about (e.g. "waitlist.ready")
+}
+
+// Notifier delivers messages to guests (SMS gateway, push service, ...)
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+// Default is the notifier used by the controllers, configured in main.go
+var Default Notifier = LogNotifier{}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OrderItemRepository reads and writes order items
+type OrderItemRepository struct {
+	collection *mongo.Collection
+}
+
+// OrderItems returns the repository for the order item collection
+func OrderItems() *OrderItemRepository {
+	return &OrderItemRepository{collection: database.OrderItemCollection}
+}
+
+// InsertMany stores new order items. The items must already have their IDs assigned.
+func (r *OrderItemRepository) InsertMany(ctx context.Context, items []models.OrderItem) (*mongo.InsertManyResult, error) {
+	docs := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		docs = append(docs, item)
+	}
+	return r.collection.InsertMany(ctx, docs)
+}
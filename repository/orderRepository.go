@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"golang-restaurant-management/database"
+	"golang-restaurant-management/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OrderRepository reads and writes orders
+type OrderRepository struct {
+	collection *mongo.Collection
+}
+
+// Orders returns the repository for the order collection
+func Orders() *OrderRepository {
+	return &OrderRepository{collection: database.OrderCollection}
+}
+
+// Insert stores a new order. The order must already have its IDs assigned.
+func (r *OrderRepository) Insert(ctx context.Context, order *models.Order) error {
+	_, err := r.collection.InsertOne(ctx, order)
+	return err
+}
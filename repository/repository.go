@@ -0,0 +1,25 @@
+// Package repository wraps the MongoDB collections behind small typed repositories.
+//
+// Every method takes a context. When that context is the mongo.SessionContext handed out by Transaction,
+// the call runs inside the transaction, so handlers can combine several repository calls atomically
+// without threading sessions through them.
+package repository
+
+import (
+	"context"
+	"golang-restaurant-management/database"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transaction runs fn inside a multi-document transaction and returns its result. The driver retries fn
+// on transient errors, so it must not have side effects outside the database.
+func Transaction(ctx context.Context, fn func(sc mongo.SessionContext) (interface{}, error)) (interface{}, error) {
+	session, err := database.Client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	return session.WithTransaction(ctx, fn)
+}
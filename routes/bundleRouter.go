@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+)
+
+// ! BundleRoutes registers combo meal (bundle) routes
+func BundleRoutes(router *gin.Engine) {
+	bundleGroup := router.Group("/bundles")
+	{
+		bundleGroup.GET("/", controller.GetBundles())                   //? Get all bundles
+		bundleGroup.GET("/:bundle_id", controller.GetBundle())          //? Get bundle by ID
+		bundleGroup.POST("/", controller.CreateBundle())                //? Create a new bundle
+		bundleGroup.PATCH("/:bundle_id", controller.UpdateBundle())     //? Update a bundle
+		bundleGroup.POST("/:bundle_id/price", controller.PriceBundle()) //? Preview the price for a set of slot choices
+	}
+}
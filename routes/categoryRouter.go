@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-restaurant-management/cache"
+	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
+)
+
+// ! CategoryRoutes registers menu category routes
+func CategoryRoutes(router *gin.Engine) {
+	categoryGroup := router.Group("/categories", middleware.InvalidateCache(cache.PublicMenu))
+	{
+		categoryGroup.GET("/", controller.GetCategories())                         //? Get all categories (?menu_id=)
+		categoryGroup.POST("/", controller.CreateCategory())                       //? Create a new category
+		categoryGroup.PATCH("/:category_id", controller.UpdateCategory())          //? Rename, move or reposition a category
+		categoryGroup.DELETE("/:category_id", controller.DeleteCategory())         //? Delete an empty category
+		categoryGroup.PUT("/:category_id/foods/order", controller.ReorderFoods())  //? Reorder the foods in a category
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
+)
+
+// ! EventRoutes registers the real-time change event streams for front-of-house and kitchen screens
+func EventRoutes(router *gin.Engine) {
+	eventGroup := router.Group("/events")
+	{
+		eventGroup.GET("/", middleware.RequireRole("admin", "manager", "staff"), controller.StreamEvents())            //? Server-Sent Events stream (?types=order,table&station_id=, Last-Event-ID to resume)
+		eventGroup.GET("/ws", middleware.RequireRole("admin", "manager", "staff"), controller.StreamEventsWebSocket()) //? WebSocket stream (?types=&station_id=&last_event_id=)
+		eventGroup.POST("/ticket", middleware.RequireRole("admin", "manager", "staff"), controller.CreateStreamTicket()) //? Single-use ticket for opening a stream from a browser (?ticket=, valid 30s)
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
+)
+
+// ! FloorRoutes registers floor area, section and shift assignment routes
+func FloorRoutes(router *gin.Engine) {
+	floorGroup := router.Group("/floor")
+	{
+		floorGroup.GET("/areas", controller.GetFloorAreas())                                                          //? Floor areas in display order
+		floorGroup.POST("/areas", middleware.RequireRole("admin"), controller.CreateFloorArea())                      //? Create a floor area (admin)
+		floorGroup.PATCH("/areas/:area_id", middleware.RequireRole("admin"), controller.UpdateFloorArea())            //? Update a floor area (admin)
+		floorGroup.DELETE("/areas/:area_id", middleware.RequireRole("admin"), controller.DeleteFloorArea())           //? Delete an empty floor area (admin)
+		floorGroup.GET("/sections", controller.GetSections())                                                         //? Sections (?area_id=)
+		floorGroup.POST("/sections", middleware.RequireRole("admin"), controller.CreateSection())                     //? Create a section (admin)
+		floorGroup.PATCH("/sections/:section_id", middleware.RequireRole("admin"), controller.UpdateSection())        //? Update a section (admin)
+		floorGroup.DELETE("/sections/:section_id", middleware.RequireRole("admin"), controller.DeleteSection())       //? Delete a section and unassign its tables (admin)
+		floorGroup.PUT("/sections/:section_id/tables", middleware.RequireRole("admin"), controller.SetSectionTables()) //? Set the tables of a section (admin)
+		floorGroup.POST("/sections/:section_id/assignments", middleware.RequireRole("admin"), controller.CreateSectionAssignment()) //? Assign a server to the section for a shift (admin)
+		floorGroup.GET("/assignments", controller.GetSectionAssignments())                                            //? Shift assignments (?section_id=&user_id=&at=)
+		floorGroup.DELETE("/assignments/:assignment_id", middleware.RequireRole("admin"), controller.DeleteSectionAssignment()) //? Remove a shift assignment (admin)
+	}
+}
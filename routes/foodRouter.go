@@ -2,17 +2,27 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"golang-restaurant-management/cache"
 	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
 )
 
 //! FoodRoutes registers food-related routes
 func FoodRoutes(router *gin.Engine) {
-	foodGroup := router.Group("/foods") 
+	foodGroup := router.Group("/foods", middleware.InvalidateCache(cache.PublicMenu))
 	{
 		foodGroup.GET("/", controller.GetFoods())              //? Get all foods
 		foodGroup.GET("/:food_id", controller.GetFood())       //? Get food by ID
 		foodGroup.POST("/", controller.CreateFood())           //? Create a new food item
 		foodGroup.PATCH("/:food_id", controller.UpdateFood())  //? Update an existing food item
+		foodGroup.POST("/:food_id/image", middleware.RequireRole("admin", "manager"), controller.UploadFoodImage()) //? Upload an image for a food item (admin, manager)
+		foodGroup.GET("/:food_id/nutrition", controller.GetFoodNutrition())  //? Nutrition for a size and modifiers (?size=L&modifiers=a,b)
+		foodGroup.GET("/:food_id/price", controller.GetFoodEffectivePrice())                //? Effective price incl. pricing rules (?at=)
+		foodGroup.GET("/:food_id/prices", controller.GetFoodPrices())                       //? Price history (?at= resolves the effective price)
+		foodGroup.POST("/:food_id/prices", controller.ScheduleFoodPrice())                  //? Set or schedule a price change
+		foodGroup.DELETE("/:food_id/prices/:price_id", controller.CancelScheduledFoodPrice()) //? Cancel a scheduled price change
+		foodGroup.PUT("/:food_id/translations/:locale", middleware.RequireRole("admin"), controller.SetFoodTranslation())       //? Set a translation (admin)
+		foodGroup.DELETE("/:food_id/translations/:locale", middleware.RequireRole("admin"), controller.DeleteFoodTranslation()) //? Delete a translation (admin)
 		// foodGroup.DELETE("/:food_id", controller.DeleteFood()) //? Delete food item
 	}
 }
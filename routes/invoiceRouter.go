@@ -9,7 +9,7 @@ import (
 func InvoiceRoutes(router *gin.Engine) {
 	invoiceGroup := router.Group("/invoices")
 	{
-		invoiceGroup.GET("/", controllers.GetInvoices())                //? Get all invoices
+		invoiceGroup.GET("/", controllers.GetInvoices())                //? Get all invoices (?order_type=delivery)
 		invoiceGroup.GET("/:invoice_id", controllers.GetInvoice())      //? Get invoice by ID
 		invoiceGroup.POST("/", controllers.CreateInvoice())             //? Create a new invoice
 		invoiceGroup.PATCH("/:invoice_id", controllers.UpdateInvoice()) //? Update an invoice
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
+)
+
+// ! KitchenRoutes registers kitchen station and kitchen display routes
+func KitchenRoutes(router *gin.Engine) {
+	kitchenGroup := router.Group("/kitchen")
+	{
+		kitchenGroup.GET("/stations", controller.GetKitchenStations())                                                  //? Kitchen stations by name
+		kitchenGroup.POST("/stations", middleware.RequireRole("admin"), controller.CreateKitchenStation())              //? Create a kitchen station (admin)
+		kitchenGroup.PATCH("/stations/:station_id", middleware.RequireRole("admin"), controller.UpdateKitchenStation()) //? Update a kitchen station (admin)
+		kitchenGroup.DELETE("/stations/:station_id", middleware.RequireRole("admin"), controller.DeleteKitchenStation()) //? Delete an unused kitchen station (admin)
+		kitchenGroup.GET("/stations/:station_id/tickets", controller.GetStationTickets())                               //? Station screen queue (?status=open|bumped)
+		kitchenGroup.GET("/tickets", controller.GetKitchenTickets())                                                    //? Tickets of all stations (?order_id=&status=)
+		kitchenGroup.POST("/tickets/:ticket_id/bump", controller.BumpKitchenTicket())                                   //? Mark the whole ticket done
+		kitchenGroup.POST("/tickets/:ticket_id/recall", controller.RecallKitchenTicket())                               //? Bring a bumped ticket back
+		kitchenGroup.POST("/tickets/:ticket_id/items/:line_id/bump", controller.BumpKitchenItem())                      //? Mark one line done
+		kitchenGroup.POST("/tickets/:ticket_id/items/:line_id/recall", controller.RecallKitchenItem())                  //? Bring a bumped line back
+	}
+}
@@ -2,17 +2,36 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"golang-restaurant-management/cache"
 	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
 )
 
 // ! MenuRoutes registers menu-related route
 func MenuRoutes(router *gin.Engine) {
-	menuGroup := router.Group("/menus")
+	menuGroup := router.Group("/menus", middleware.InvalidateCache(cache.PublicMenu))
 	{
 		menuGroup.GET("/", controller.GetMenus())              //? Get all menus
+		menuGroup.GET("/export", controller.ExportMenus())     //? Export menus and foods (?format=csv|json)
+		menuGroup.POST("/import", controller.ImportMenus())    //? Import menus and foods (?format=csv|json&dry_run=true)
 		menuGroup.GET("/:menu_id", controller.GetMenu())       //? Get menu by ID
 		menuGroup.POST("/", controller.CreateMenu())           //? Create a new menu
 		menuGroup.PATCH("/:menu_id", controller.UpdateMenu())    //? Update an menu
+		menuGroup.GET("/:menu_id/tree", controller.GetMenuTree())                         //? Get the fully nested menu
+		menuGroup.PUT("/:menu_id/categories/order", controller.ReorderCategories())       //? Reorder sibling categories
+		menuGroup.PUT("/:menu_id/translations/:locale", middleware.RequireRole("admin"), controller.SetMenuTranslation())       //? Set a translation (admin)
+		menuGroup.DELETE("/:menu_id/translations/:locale", middleware.RequireRole("admin"), controller.DeleteMenuTranslation()) //? Delete a translation (admin)
+		menuGroup.POST("/:menu_id/draft", controller.StartMenuDraft())                    //? Open a draft copy of the live menu
+		menuGroup.GET("/:menu_id/draft", controller.GetMenuDraft())                       //? Get the open draft
+		menuGroup.PATCH("/:menu_id/draft", controller.UpdateMenuDraft())                  //? Update the draft menu fields
+		menuGroup.DELETE("/:menu_id/draft", controller.DiscardMenuDraft())                //? Discard the draft
+		menuGroup.POST("/:menu_id/draft/foods", controller.AddDraftFood())                //? Add a food to the draft
+		menuGroup.PATCH("/:menu_id/draft/foods/:food_id", controller.UpdateDraftFood())   //? Update a food in the draft
+		menuGroup.DELETE("/:menu_id/draft/foods/:food_id", controller.RemoveDraftFood())  //? Remove a food from the draft
+		menuGroup.GET("/:menu_id/preview", controller.PreviewMenuDraft())                 //? Preview the draft and its changes
+		menuGroup.POST("/:menu_id/publish", middleware.RequireRole("admin", "manager"), controller.PublishMenuDraft()) //? Publish the draft atomically (admin, manager)
+		menuGroup.GET("/:menu_id/versions", controller.GetMenuVersions())                 //? List published versions
+		menuGroup.POST("/:menu_id/versions/:version/rollback", middleware.RequireRole("admin", "manager"), controller.RollbackMenuVersion()) //? Republish an earlier version (admin, manager)
 		// menuGroup.DELETE("/:menu_id", controller.DeleteMenu) //? Delete an menu
 	}
 }
@@ -18,4 +18,6 @@ func OrderItemRoutes(router *gin.Engine) {
 
 	//! This route is registered separately at the root level
 	router.GET("/orderItem-order/:order_id", controller.GetOrderItemsByOrder()) //? Get order items by order ID
+	router.GET("/orderItem-order/:order_id/kitchen", controller.GetKitchenTicket()) //? Get the kitchen ticket for an order
+	router.GET("/orderItem-order/:order_id/nutrition", controller.GetOrderNutrition()) //? Get the nutrition totals for an order
 }
@@ -9,10 +9,15 @@ import (
 func OrderRoutes(router *gin.Engine) {
 	orderGroup := router.Group("/orders")
 	{
-		orderGroup.GET("/", controller.GetOrders())               //? Get all orders
+		orderGroup.GET("/", controller.GetOrders())               //? Get all orders (?status=open,submitted&type=takeout)
+		orderGroup.GET("/summary", controller.GetOrderSummary()) //? Order counts and revenue per order type (?from=&to=&type=)
 		orderGroup.GET("/:order_id", controller.GetOrder())       //? Get order by ID
 		orderGroup.POST("/", controller.CreateOrder())            //? Create a new order
 		orderGroup.PATCH("/:order_id", controller.UpdateOrder())    //? Update an existing order
+		orderGroup.PATCH("/:order_id/status", controller.UpdateOrderStatus()) //? Move the order through its lifecycle
+		orderGroup.POST("/:order_id/transfer", controller.TransferOrder()) //? Move the whole order to another table
+		orderGroup.POST("/:order_id/items/transfer", controller.TransferOrderItems()) //? Move some items to another order or table
+		orderGroup.GET("/:order_id/transfers", controller.GetOrderTransfers()) //? History of moves the order took part in
 		// orderGroup.DELETE("/:order_id", controller.DeleteOrder()) //? Delete an order
 	}
 }
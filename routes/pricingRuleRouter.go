@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-restaurant-management/cache"
+	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
+)
+
+// ! PricingRuleRoutes registers time-based pricing rule routes (happy hours, lunch specials)
+func PricingRuleRoutes(router *gin.Engine) {
+	ruleGroup := router.Group("/pricing-rules", middleware.InvalidateCache(cache.PublicMenu))
+	{
+		ruleGroup.GET("/", controller.GetPricingRules())                                                   //? Get all pricing rules (?scope=&target_id=)
+		ruleGroup.GET("/:rule_id", controller.GetPricingRule())                                            //? Get pricing rule by ID
+		ruleGroup.POST("/", middleware.RequireRole("admin"), controller.CreatePricingRule())               //? Create a pricing rule (admin)
+		ruleGroup.PATCH("/:rule_id", middleware.RequireRole("admin"), controller.UpdatePricingRule())      //? Update a pricing rule (admin)
+		ruleGroup.DELETE("/:rule_id", middleware.RequireRole("admin"), controller.DeletePricingRule())     //? Delete a pricing rule (admin)
+	}
+}
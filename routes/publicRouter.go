@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+)
+
+// ! PublicRoutes registers the unauthenticated, read-only menu API used by table QR codes
+func PublicRoutes(router *gin.Engine) {
+	publicGroup := router.Group("/public")
+	{
+		publicGroup.GET("/menus", controller.GetPublicMenus())         //? Menus currently on offer
+		publicGroup.GET("/menus/:menu_id", controller.GetPublicMenu()) //? Live menu with categories and available foods
+		publicGroup.GET("/tables/:table_id", controller.GetPublicTable()) //? Resolve a signed table link from a QR code (?v=&sig=)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+)
+
+// ! ReservationRoutes registers table booking routes
+func ReservationRoutes(router *gin.Engine) {
+	reservationGroup := router.Group("/reservations")
+	{
+		reservationGroup.GET("/", controller.GetReservations())                                  //? Get reservations (?date=YYYY-MM-DD&status=)
+		reservationGroup.GET("/availability", controller.GetReservationAvailability())           //? Free tables (?time=&party_size=&duration=)
+		reservationGroup.GET("/:reservation_id", controller.GetReservation())                    //? Get reservation by ID
+		reservationGroup.POST("/", controller.CreateReservation())                               //? Book a table (assigned automatically unless table_id is given)
+		reservationGroup.PATCH("/:reservation_id", controller.UpdateReservation())               //? Change a booking
+		reservationGroup.POST("/:reservation_id/cancel", controller.CancelReservation())         //? Cancel a booking
+		reservationGroup.POST("/:reservation_id/no-show", controller.MarkReservationNoShow())    //? Mark a party as not shown up
+		reservationGroup.POST("/:reservation_id/seat", controller.SeatReservation())             //? Seat the party and open the table's order
+	}
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+)
+
+// ! SearchRoutes registers search routes
+func SearchRoutes(router *gin.Engine) {
+	router.GET("/search", controller.Search()) //? Search foods and menus (?q=&menu_id=&min_price=&max_price=&available=)
+}
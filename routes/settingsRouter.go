@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-restaurant-management/cache"
+	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
+)
+
+// ! SettingsRoutes registers restaurant settings routes
+func SettingsRoutes(router *gin.Engine) {
+	settingsGroup := router.Group("/settings", middleware.InvalidateCache(cache.PublicMenu))
+	{
+		settingsGroup.GET("/", controller.GetSettings())                                        //? Get restaurant settings
+		settingsGroup.PATCH("/", middleware.RequireRole("admin"), controller.UpdateSettings()) //? Update currency / rounding rule (admin)
+	}
+}
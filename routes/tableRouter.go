@@ -3,16 +3,25 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
 )
 
 // TableRoutes registers table-related routes
 func TableRoutes(router *gin.Engine) {
 	tableGroup := router.Group("/tables")
 	{
-		tableGroup.GET("/", controller.GetTables())               //? Get all tables
+		tableGroup.GET("/", controller.GetTables())               //? Get all tables (?status=&area_id=&section_id=, ?view=floor&at= for the floor plan)
+		tableGroup.GET("/qr/sheet", middleware.RequireRole("admin", "manager"), controller.GetTableQRSheet()) //? Printable PDF of every table's QR code (admin, manager)
+		tableGroup.POST("/merge", controller.MergeTables())        //? Push tables together into a temporary combined table
+		tableGroup.POST("/qr/rotate", middleware.RequireRole("admin", "manager"), controller.RotateAllTableQR()) //? Invalidate all QR codes (admin, manager)
 		tableGroup.GET("/:table_id", controller.GetTable())       //? Get table by ID
 		tableGroup.POST("/", controller.CreateTable())            //? Create a new table
 		tableGroup.PATCH("/:table_id", controller.UpdateTable())    //? Update a table
+		tableGroup.PATCH("/:table_id/status", controller.UpdateTableStatus()) //? Move the table through its lifecycle
+		tableGroup.POST("/:table_id/split", controller.SplitTable()) //? Split a combined table back into its tables
+		tableGroup.GET("/:table_id/transfers", controller.GetTableTransfers()) //? History of moves to and from the table
+		tableGroup.GET("/:table_id/qr", controller.GetTableQR())  //? QR code for the table's signed link (?format=png|svg&size=)
+		tableGroup.POST("/:table_id/qr/rotate", middleware.RequireRole("admin"), controller.RotateTableQR()) //? Invalidate the table's QR code (admin)
 		// tableGroup.DELETE("/:table_id", controller.DeleteTable()) //? Delete a table
 	}
 }
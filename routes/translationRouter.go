@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
+)
+
+// ! TranslationRoutes registers menu translation admin routes
+func TranslationRoutes(router *gin.Engine) {
+	router.GET("/translations/report", middleware.RequireRole("admin"), controller.GetTranslationReport()) //? Translation completeness per locale (admin)
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+)
+
+// ! UploadRoutes serves uploaded files (food images, avatars) publicly
+func UploadRoutes(router *gin.Engine) {
+	router.GET("/uploads/*filepath", controller.ServeUpload()) //? Serve an uploaded file
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	controller "golang-restaurant-management/controllers"
+	"golang-restaurant-management/middleware"
 )
 
 //! UserRoutes registers user-related routes
@@ -13,5 +14,7 @@ func UserRoutes(router *gin.Engine) {
 		userGroup.POST("/login", controller.Login())     //? Authenticate a user
 		userGroup.GET("/", controller.GetUsers())        //? Get all users
 		userGroup.GET("/:user_id", controller.GetUser()) //? Get user by ID
+		userGroup.POST("/:user_id/avatar", middleware.Authentication(), controller.UploadAvatar()) //? Upload the user's avatar
+		userGroup.PATCH("/:user_id/role", middleware.Authentication(), middleware.RequireRole("admin"), controller.UpdateUserRole()) //? Change a user's role (admins only)
 	}
 }
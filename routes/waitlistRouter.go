@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "golang-restaurant-management/controllers"
+)
+
+// ! WaitlistRoutes registers walk-in waitlist routes
+func WaitlistRoutes(router *gin.Engine) {
+	waitlistGroup := router.Group("/waitlist")
+	{
+		waitlistGroup.GET("/", controller.GetWaitlist())                           //? Waiting parties with current estimates
+		waitlistGroup.GET("/estimate", controller.GetWaitEstimate())               //? Quote the wait for a new party (?party_size=)
+		waitlistGroup.POST("/", controller.JoinWaitlist())                         //? Add a party to the waitlist
+		waitlistGroup.PATCH("/:entry_id", controller.UpdateWaitlistEntry())        //? Change a waiting party
+		waitlistGroup.POST("/:entry_id/notify", controller.NotifyWaitlistEntry())  //? Tell the party its table is ready
+		waitlistGroup.POST("/:entry_id/seat", controller.SeatWaitlistEntry())      //? Seat the party at a table
+		waitlistGroup.POST("/:entry_id/leave", controller.LeaveWaitlist())         //? Remove a party that left
+	}
+}
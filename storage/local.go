@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage stores files on the local filesystem below Root
+type LocalStorage struct {
+	Root    string // Directory files are written to
+	BaseURL string // URL prefix the files are served from (e.g. /uploads)
+}
+
+// NewLocalStorage creates the root directory if needed and returns a LocalStorage
+func NewLocalStorage(root, baseURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStorage{Root: root, BaseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+func (s *LocalStorage) path(key string) (string, string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	return cleaned, filepath.Join(s.Root, filepath.FromSlash(cleaned)), nil
+}
+
+// Save writes data to a temporary file and renames it into place
+func (s *LocalStorage) Save(ctx context.Context, key string, data io.Reader, contentType string) (string, error) {
+	cleaned, target, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", fmt.Errorf("failed to store file: %w", err)
+	}
+
+	return s.BaseURL + "/" + cleaned, nil
+}
+
+// Open opens the file stored under key
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	cleaned, target, err := s.path(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	file, err := os.Open(target)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ObjectInfo{}, ErrNotFound
+		}
+		return nil, ObjectInfo{}, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() {
+		file.Close()
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(cleaned))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return file, ObjectInfo{
+		Key:         cleaned,
+		Size:        stat.Size(),
+		ContentType: contentType,
+		ModTime:     stat.ModTime(),
+	}, nil
+}
+
+// Delete removes the file stored under key; missing files are not an error
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	_, target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// KeyFromURL strips BaseURL from a URL produced by Save
+func (s *LocalStorage) KeyFromURL(url string) (string, bool) {
+	if !strings.HasPrefix(url, s.BaseURL+"/") {
+		return "", false
+	}
+	key, err := CleanKey(strings.TrimPrefix(url, s.BaseURL+"/"))
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
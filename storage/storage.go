@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when a stored object does not exist
+var ErrNotFound = errors.New("storage: object not found")
+
+// ErrInvalidKey is returned for keys that would escape the storage root
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage is a backend for uploaded files (images, avatars, ...)
+type Storage interface {
+	// Save writes data under key and returns the public URL of the object
+	Save(ctx context.Context, key string, data io.Reader, contentType string) (string, error)
+	// Open returns a reader for the object stored under key
+	Open(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	// Delete removes the object stored under key
+	Delete(ctx context.Context, key string) error
+	// KeyFromURL maps a URL returned by Save back to its key
+	KeyFromURL(url string) (string, bool)
+}
+
+// Default is the storage backend used by the controllers, configured in main.go
+var Default Storage
+
+// CleanKey normalises a key and rejects anything pointing outside the storage root
+func CleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + strings.TrimSpace(key))
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if cleaned == "" || cleaned == "." || strings.HasPrefix(cleaned, "..") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}